@@ -22,6 +22,12 @@ func main() {
         &v1.SeaTableListColumns{},
         &v1.SeaTableListViews{},
         &v1.SeaTableDownloadFile{},
+        &v1.SeaTableAccountConnect{},
+        &v1.SeaTableDisconnect{},
+        &v1.SeaTableTestConnection{},
+        &v1.SeaTableUpsert{},
+        &v1.SeaTableOpenCursor{},
+        &v1.SeaTableNextPage{},
     )
     runtime.Start()
 }
@@ -0,0 +1,64 @@
+package seatable
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Workspace is a workspace visible to an account, with the bases it holds.
+type Workspace struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	OwnerName string `json:"owner_name"`
+	OwnerType string `json:"owner_type"`
+	Bases     []Base `json:"table_list"`
+}
+
+// Base identifies a base inside a workspace.
+type Base struct {
+	UUID        string `json:"uuid"`
+	Name        string `json:"name"`
+	WorkspaceID int    `json:"workspace_id"`
+}
+
+// Login exchanges email and password for an account token. A nil httpClient
+// uses DefaultHTTPClient.
+func Login(ctx context.Context, httpClient *http.Client, server, username, password string) (string, *Response, error) {
+	if httpClient == nil {
+		httpClient = DefaultHTTPClient
+	}
+	url := fmt.Sprintf("%s/api2/auth-token/", TrimServerURL(server))
+	body := map[string]any{
+		"username": username,
+		"password": password,
+	}
+	var out struct {
+		Token string `json:"token"`
+	}
+	resp, err := send(ctx, httpClient, "POST", url, "", body, &out)
+	if err != nil {
+		return "", resp, err
+	}
+	if out.Token == "" {
+		return "", resp, fmt.Errorf("token is empty")
+	}
+	return out.Token, resp, nil
+}
+
+// ListWorkspaces returns the workspaces and bases visible to an account. A
+// nil httpClient uses DefaultHTTPClient.
+func ListWorkspaces(ctx context.Context, httpClient *http.Client, server, accountToken string) ([]Workspace, *Response, error) {
+	if httpClient == nil {
+		httpClient = DefaultHTTPClient
+	}
+	url := fmt.Sprintf("%s/api/v2.1/workspaces/", TrimServerURL(server))
+	var out struct {
+		Workspaces []Workspace `json:"workspace_list"`
+	}
+	resp, err := send(ctx, httpClient, "GET", url, "Token "+accountToken, nil, &out)
+	if err != nil {
+		return nil, resp, err
+	}
+	return out.Workspaces, resp, nil
+}
@@ -0,0 +1,253 @@
+package seatable
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// tokenRefreshMargin is how long before expiry a base access token is renewed.
+const tokenRefreshMargin = 5 * time.Minute
+
+var (
+	// ErrClientClosed is returned by calls on a client after Close.
+	ErrClientClosed = errors.New("seatable: client is closed")
+
+	// ErrBaseMismatch is returned by UseAPIToken when the token belongs to a
+	// different base than the client's BaseUUID.
+	ErrBaseMismatch = errors.New("seatable: API token does not belong to the given base")
+)
+
+// AccessTokenResponse is returned when a base access token is issued.
+type AccessTokenResponse struct {
+	AppName      string `json:"app_name"`
+	AccessToken  string `json:"access_token"`
+	DTableUUID   string `json:"dtable_uuid"`
+	DTableServer string `json:"dtable_server"`
+	DTableSocket string `json:"dtable_socket"`
+	DTableDB     string `json:"dtable_db"`
+	WorkspaceID  int    `json:"workspace_id"`
+	DTableName   string `json:"dtable_name"`
+}
+
+// AccessTokenClaims holds the fields read from a base access token (JWT).
+type AccessTokenClaims struct {
+	Exp        int64  `json:"exp"`
+	DTableUUID string `json:"dtable_uuid"`
+	Permission string `json:"permission"`
+}
+
+// secret holds a credential in a byte slice so it can be zeroed when the
+// client is closed. Callers should avoid keeping copies of String().
+type secret []byte
+
+func newSecret(v string) secret { return secret(v) }
+
+func (s secret) String() string { return string(s) }
+
+func (s secret) empty() bool { return len(s) == 0 }
+
+func (s *secret) wipe() {
+	for i := range *s {
+		(*s)[i] = 0
+	}
+	*s = nil
+}
+
+// IsAccessToken reports whether token looks like a base access token (JWT)
+// rather than a long-lived API token.
+func IsAccessToken(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// ParseAccessToken decodes the claims of a base access token without
+// verifying its signature; the server does that on every request.
+func ParseAccessToken(token string) (*AccessTokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("access token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("decode access token payload: %w", err)
+	}
+	var claims AccessTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("parse access token payload: %w", err)
+	}
+	return &claims, nil
+}
+
+// UseAPIToken authenticates the client with a long-lived API token. The token
+// is exchanged for a base access token, which is renewed automatically.
+func (c *Client) UseAPIToken(ctx context.Context, apiToken string) error {
+	resp, err := c.exchangeAPIToken(ctx, apiToken)
+	if err != nil {
+		return err
+	}
+	if c.BaseUUID != "" && resp.DTableUUID != "" && !strings.EqualFold(c.BaseUUID, resp.DTableUUID) {
+		return ErrBaseMismatch
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.apiToken = newSecret(apiToken)
+	c.applyAccessToken(resp)
+	return nil
+}
+
+// UseAccessToken authenticates the client with a base access token as-is. It
+// cannot be renewed once it expires.
+func (c *Client) UseAccessToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = newSecret(token)
+	if claims, err := ParseAccessToken(token); err == nil {
+		if claims.Exp > 0 {
+			c.expiresAt = time.Unix(claims.Exp, 0)
+		}
+		if c.BaseUUID == "" {
+			c.BaseUUID = claims.DTableUUID
+		}
+	}
+}
+
+// UseAccountToken authenticates the client on behalf of an account. The base
+// is addressed by workspace and name; see Login and ListWorkspaces.
+func (c *Client) UseAccountToken(ctx context.Context, accountToken string, workspaceID int, baseName string) error {
+	resp, err := c.accountBaseToken(ctx, accountToken, workspaceID, baseName)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.accountToken = newSecret(accountToken)
+	if c.WorkspaceID == 0 {
+		c.WorkspaceID = workspaceID
+	}
+	if c.BaseName == "" {
+		c.BaseName = baseName
+	}
+	c.applyAccessToken(resp)
+	return nil
+}
+
+// exchangeAPIToken trades a long-lived API token for a base access token.
+func (c *Client) exchangeAPIToken(ctx context.Context, apiToken string) (*AccessTokenResponse, error) {
+	url := fmt.Sprintf("%s/api/v2.1/dtable/app-access-token/", c.Server)
+	var out AccessTokenResponse
+	if _, err := send(ctx, c.HTTPClient(), "GET", url, "Token "+apiToken, nil, &out); err != nil {
+		return nil, err
+	}
+	if out.AccessToken == "" {
+		return nil, fmt.Errorf("access_token is empty")
+	}
+	return &out, nil
+}
+
+// accountBaseToken requests a base access token on behalf of an account.
+func (c *Client) accountBaseToken(ctx context.Context, accountToken string, workspaceID int, baseName string) (*AccessTokenResponse, error) {
+	u := fmt.Sprintf("%s/api/v2.1/workspace/%d/dtable/%s/access-token/", c.Server, workspaceID, url.PathEscape(baseName))
+	var out AccessTokenResponse
+	if _, err := send(ctx, c.HTTPClient(), "GET", u, "Token "+accountToken, nil, &out); err != nil {
+		return nil, err
+	}
+	if out.AccessToken == "" {
+		return nil, fmt.Errorf("access_token is empty")
+	}
+	return &out, nil
+}
+
+// CanRefresh reports whether the client holds credentials to renew its token.
+func (c *Client) CanRefresh() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.apiToken.empty() || !c.accountToken.empty()
+}
+
+// AccessToken returns the current base access token, renewing it first when
+// it is missing or about to expire.
+func (c *Client) AccessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return "", ErrClientClosed
+	}
+	if (!c.apiToken.empty() || !c.accountToken.empty()) && (c.token.empty() || (!c.expiresAt.IsZero() && time.Until(c.expiresAt) < tokenRefreshMargin)) {
+		if err := c.refreshLocked(ctx); err != nil {
+			return "", err
+		}
+	}
+	return c.token.String(), nil
+}
+
+// RenewAccessToken forces a refresh unless another caller already replaced
+// the stale token in the meantime.
+func (c *Client) RenewAccessToken(ctx context.Context, stale string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return "", ErrClientClosed
+	}
+	if c.token.String() == stale {
+		if err := c.refreshLocked(ctx); err != nil {
+			return "", err
+		}
+	}
+	return c.token.String(), nil
+}
+
+// refreshLocked obtains a new access token with whichever long-lived
+// credential the client holds. c.mu must be held.
+func (c *Client) refreshLocked(ctx context.Context) error {
+	var (
+		resp *AccessTokenResponse
+		err  error
+	)
+	switch {
+	case !c.apiToken.empty():
+		resp, err = c.exchangeAPIToken(ctx, c.apiToken.String())
+	case !c.accountToken.empty():
+		resp, err = c.accountBaseToken(ctx, c.accountToken.String(), c.WorkspaceID, c.BaseName)
+	default:
+		return ErrClientClosed
+	}
+	if err != nil {
+		return err
+	}
+	c.applyAccessToken(resp)
+	return nil
+}
+
+// applyAccessToken stores the result of a token exchange on the client. Base
+// details are only filled in once so they can be read without locking. c.mu
+// must be held.
+func (c *Client) applyAccessToken(resp *AccessTokenResponse) {
+	c.token.wipe()
+	c.token = newSecret(resp.AccessToken)
+	c.expiresAt = time.Time{}
+	if claims, err := ParseAccessToken(resp.AccessToken); err == nil && claims.Exp > 0 {
+		c.expiresAt = time.Unix(claims.Exp, 0)
+	}
+	if c.BaseUUID == "" {
+		c.BaseUUID = resp.DTableUUID
+	}
+	if c.DTableServer == "" {
+		c.DTableServer = TrimServerURL(resp.DTableServer)
+	}
+	if c.DTableDB == "" {
+		c.DTableDB = TrimServerURL(resp.DTableDB)
+	}
+	if c.WorkspaceID == 0 {
+		c.WorkspaceID = resp.WorkspaceID
+	}
+	if c.BaseName == "" {
+		c.BaseName = resp.DTableName
+	}
+}
@@ -0,0 +1,157 @@
+package seatable
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tokenServer issues base access tokens for the API token "api" and serves
+// the SQL endpoint to requests carrying the latest one.
+type tokenServer struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	issued  int
+	current string
+	revoked bool
+}
+
+func testAccessToken(n int, exp time.Time) string {
+	payload, _ := json.Marshal(AccessTokenClaims{Exp: exp.Unix(), DTableUUID: "base"})
+	return fmt.Sprintf("header.%s.sig%d", base64.RawURLEncoding.EncodeToString(payload), n)
+}
+
+func (s *tokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	auth := r.Header.Get("Authorization")
+
+	if r.URL.Path == "/api/v2.1/dtable/app-access-token/" {
+		if auth != "Token api" {
+			http.Error(w, `{"detail":"Invalid token"}`, http.StatusForbidden)
+			return
+		}
+		s.issued++
+		s.current = testAccessToken(s.issued, time.Now().Add(s.ttl))
+		s.revoked = false
+		json.NewEncoder(w).Encode(AccessTokenResponse{
+			AccessToken:  s.current,
+			DTableUUID:   "base",
+			DTableServer: "https://dtable.example.com/",
+			WorkspaceID:  7,
+			DTableName:   "Orders",
+		})
+		return
+	}
+	if s.revoked || auth != "Bearer "+s.current {
+		http.Error(w, `{"detail":"Token expired"}`, http.StatusUnauthorized)
+		return
+	}
+	json.NewEncoder(w).Encode(SQLResult{Success: true, Results: []Row{}})
+}
+
+// revoke makes the server reject the current access token.
+func (s *tokenServer) revoke() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revoked = true
+	return s.current
+}
+
+func newTokenClient(t *testing.T, s *tokenServer) *Client {
+	t.Helper()
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+	return NewClient(srv.URL, srv.Client())
+}
+
+func TestUseAPIToken(t *testing.T) {
+	s := &tokenServer{ttl: time.Hour}
+	c := newTokenClient(t, s)
+	if err := c.UseAPIToken(context.Background(), "api"); err != nil {
+		t.Fatal(err)
+	}
+	if c.BaseUUID != "base" || c.DTableServer != "https://dtable.example.com" || c.WorkspaceID != 7 || c.BaseName != "Orders" {
+		t.Errorf("base details = %q %q %d %q", c.BaseUUID, c.DTableServer, c.WorkspaceID, c.BaseName)
+	}
+	if until := time.Until(c.ExpiresAt()); until < 59*time.Minute || until > time.Hour {
+		t.Errorf("token expires in %v, want about an hour", until)
+	}
+	if !c.CanRefresh() {
+		t.Error("CanRefresh = false after UseAPIToken")
+	}
+
+	if err := c.UseAPIToken(context.Background(), "wrong"); !IsStatus(err, http.StatusForbidden) {
+		t.Errorf("invalid API token: err = %v, want 403", err)
+	}
+
+	other := newTokenClient(t, s)
+	other.BaseUUID = "another-base"
+	if err := other.UseAPIToken(context.Background(), "api"); !errors.Is(err, ErrBaseMismatch) {
+		t.Errorf("foreign base: err = %v, want ErrBaseMismatch", err)
+	}
+}
+
+func TestAccessTokenRefresh(t *testing.T) {
+	tests := []struct {
+		name   string
+		ttl    time.Duration
+		issued int
+	}{
+		{"valid token is reused", time.Hour, 1},
+		{"token near expiry is renewed", time.Minute, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &tokenServer{ttl: tt.ttl}
+			c := newTokenClient(t, s)
+			if err := c.UseAPIToken(context.Background(), "api"); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 2; i++ {
+				if _, _, err := c.QuerySQL(context.Background(), "SELECT * FROM t", nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if s.issued != tt.issued {
+				t.Errorf("issued %d tokens, want %d", s.issued, tt.issued)
+			}
+		})
+	}
+}
+
+func TestRenewOnUnauthorized(t *testing.T) {
+	s := &tokenServer{ttl: time.Hour}
+	c := newTokenClient(t, s)
+	if err := c.UseAPIToken(context.Background(), "api"); err != nil {
+		t.Fatal(err)
+	}
+	s.revoke()
+	if _, resp, err := c.QuerySQL(context.Background(), "SELECT * FROM t", nil); err != nil {
+		t.Fatalf("QuerySQL after revocation: %v (resp %+v)", err, resp)
+	}
+	if s.issued != 2 {
+		t.Errorf("issued %d tokens, want 2", s.issued)
+	}
+
+	// A client without a long-lived credential cannot renew and reports the
+	// 401 as is.
+	plain := newTokenClient(t, s)
+	plain.BaseUUID = "base"
+	plain.UseAccessToken(s.revoke())
+	_, resp, err := plain.QuerySQL(context.Background(), "SELECT * FROM t", nil)
+	if !IsStatus(err, http.StatusUnauthorized) || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("access token client: err = %v, resp = %+v, want 401", err, resp)
+	}
+	if s.issued != 2 {
+		t.Errorf("issued %d tokens, want 2", s.issued)
+	}
+}
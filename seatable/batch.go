@@ -0,0 +1,280 @@
+package seatable
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// MaxBatchRows is the largest number of rows SeaTable accepts in one batch
+// request.
+const MaxBatchRows = 1000
+
+// BatchOptions configures the chunked batch calls.
+type BatchOptions struct {
+	// ChunkSize is the number of rows per request, capped at MaxBatchRows.
+	ChunkSize int
+	// Parallelism is the number of chunks sent at the same time. Values
+	// below 1 mean one chunk at a time.
+	Parallelism int
+	// StopOnError stops sending further chunks after the first failure.
+	// Chunks already in flight still complete.
+	StopOnError bool
+}
+
+// ChunkResult reports the outcome of one chunk of a batch.
+type ChunkResult struct {
+	Index      int      `json:"index"`
+	Start      int      `json:"start"`
+	Count      int      `json:"count"`
+	Succeeded  int      `json:"succeeded"`
+	Failed     int      `json:"failed"`
+	Skipped    bool     `json:"skipped,omitempty"`
+	RowIDs     []string `json:"rowIds,omitempty"`
+	StatusCode int      `json:"statusCode,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// RowFailure is a row that was not written. Index is the position of the
+// row in the input; RowID is empty for appended rows.
+type RowFailure struct {
+	Index int    `json:"index"`
+	RowID string `json:"rowId,omitempty"`
+	Error string `json:"error"`
+}
+
+// BatchResult is the combined outcome of a chunked batch. RowIDs are in
+// input order and only include rows of successful chunks. Rows of skipped
+// chunks are neither succeeded nor failed.
+type BatchResult struct {
+	RowIDs    []string      `json:"rowIds"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Skipped   int           `json:"skipped"`
+	Failures  []RowFailure  `json:"failures"`
+	Chunks    []ChunkResult `json:"chunks"`
+}
+
+// FirstError returns the first failed chunk, or nil.
+func (r *BatchResult) FirstError() *ChunkResult {
+	for i := range r.Chunks {
+		if r.Chunks[i].Error != "" {
+			return &r.Chunks[i]
+		}
+	}
+	return nil
+}
+
+// AppendRowsChunked appends rows in chunks of up to MaxBatchRows. A failing
+// chunk is recorded in the result and does not stop the others unless
+// opts.StopOnError is set; a cancelled context or closed client always
+// aborts the batch.
+func (c *Client) AppendRowsChunked(ctx context.Context, table string, rows []Row, opts *BatchOptions) (*BatchResult, error) {
+	return c.runChunks(ctx, len(rows), nil, opts, func(ctx context.Context, res *ChunkResult) error {
+		out, resp, err := c.AppendRows(ctx, table, rows[res.Start:res.Start+res.Count])
+		if resp != nil {
+			res.StatusCode = resp.StatusCode
+		}
+		if err != nil {
+			return err
+		}
+		for _, r := range out.RowIDs {
+			res.RowIDs = append(res.RowIDs, r.ID())
+		}
+		return nil
+	})
+}
+
+// UpdateRowsChunked is UpdateRows split into chunks like AppendRowsChunked.
+func (c *Client) UpdateRowsChunked(ctx context.Context, table string, updates []RowUpdate, opts *BatchOptions) (*BatchResult, error) {
+	ids := make([]string, len(updates))
+	for i, u := range updates {
+		ids[i] = u.RowID
+	}
+	return c.runChunks(ctx, len(updates), ids, opts, func(ctx context.Context, res *ChunkResult) error {
+		resp, err := c.UpdateRows(ctx, table, updates[res.Start:res.Start+res.Count])
+		if resp != nil {
+			res.StatusCode = resp.StatusCode
+		}
+		return err
+	})
+}
+
+// DeleteRowsChunked is DeleteRows split into chunks like AppendRowsChunked.
+func (c *Client) DeleteRowsChunked(ctx context.Context, table string, rowIDs []string, opts *BatchOptions) (*BatchResult, error) {
+	return c.runChunks(ctx, len(rowIDs), rowIDs, opts, func(ctx context.Context, res *ChunkResult) error {
+		resp, err := c.DeleteRows(ctx, table, rowIDs[res.Start:res.Start+res.Count])
+		if resp != nil {
+			res.StatusCode = resp.StatusCode
+		}
+		return err
+	})
+}
+
+// InsertRows inserts rows one by one above or below the anchor row so they
+// end up next to it in the given order. Each row is reported as a chunk of
+// its own; opts.ChunkSize and opts.Parallelism are ignored.
+func (c *Client) InsertRows(ctx context.Context, table string, rows []Row, anchorRowID, position string, opts *BatchOptions) (*BatchResult, error) {
+	if opts == nil {
+		opts = &BatchOptions{}
+	}
+	result := &BatchResult{RowIDs: []string{}, Failures: []RowFailure{}, Chunks: make([]ChunkResult, len(rows))}
+	for i := range rows {
+		result.Chunks[i] = ChunkResult{Index: i, Start: i, Count: 1, Skipped: true}
+	}
+
+	// Inserting below the same anchor pushes earlier inserts down, so rows
+	// go in last to first. Above the anchor they go in first to last.
+	order := make([]int, len(rows))
+	for i := range order {
+		order[i] = i
+		if position != InsertAbove {
+			order[i] = len(rows) - 1 - i
+		}
+	}
+
+	var abort error
+	for _, i := range order {
+		if abort = ctx.Err(); abort != nil {
+			break
+		}
+		res := &result.Chunks[i]
+		res.Skipped = false
+		id, resp, err := c.InsertRow(ctx, table, rows[i], anchorRowID, position)
+		if resp != nil {
+			res.StatusCode = resp.StatusCode
+		}
+		if err == nil {
+			res.Succeeded = 1
+			if id != "" {
+				res.RowIDs = []string{id}
+			}
+			continue
+		}
+		res.Failed = 1
+		res.Error = chunkError(err)
+		if errors.Is(err, ErrClientClosed) || ctx.Err() != nil {
+			abort = err
+			break
+		}
+		if opts.StopOnError {
+			break
+		}
+	}
+
+	result.collect(nil)
+	return result, abort
+}
+
+// runChunks splits n items into chunks and calls send for each of them,
+// running up to opts.Parallelism calls at once. Errors returned by send are
+// stored on the chunk. When ids is given, a successful chunk reports the ids
+// of its items and failures name the row they belong to.
+func (c *Client) runChunks(ctx context.Context, n int, ids []string, opts *BatchOptions, send func(context.Context, *ChunkResult) error) (*BatchResult, error) {
+	if opts == nil {
+		opts = &BatchOptions{}
+	}
+	size := opts.ChunkSize
+	if size <= 0 || size > MaxBatchRows {
+		size = MaxBatchRows
+	}
+	workers := opts.Parallelism
+	if workers < 1 {
+		workers = 1
+	}
+
+	result := &BatchResult{RowIDs: []string{}, Failures: []RowFailure{}, Chunks: []ChunkResult{}}
+	for start := 0; start < n; start += size {
+		result.Chunks = append(result.Chunks, ChunkResult{
+			Index:   len(result.Chunks),
+			Start:   start,
+			Count:   min(size, n-start),
+			Skipped: true,
+		})
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		abort  error
+		failed bool
+	)
+	// busy holds a slot for every chunk being sent. A slot is only freed
+	// once the chunk's outcome is recorded, so StopOnError sees a failure
+	// before the next chunk is handed out.
+	jobs := make(chan *ChunkResult)
+	busy := make(chan struct{}, workers)
+	for w := 0; w < min(workers, len(result.Chunks)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for res := range jobs {
+				if err := send(ctx, res); err != nil {
+					res.Failed = res.Count
+					res.Error = chunkError(err)
+					mu.Lock()
+					failed = true
+					if abort == nil && (ctx.Err() != nil || errors.Is(err, ErrClientClosed)) {
+						abort = err
+					}
+					mu.Unlock()
+				} else {
+					res.Succeeded = res.Count
+					if ids != nil {
+						res.RowIDs = ids[res.Start : res.Start+res.Count]
+					}
+				}
+				<-busy
+			}
+		}()
+	}
+
+	for i := range result.Chunks {
+		busy <- struct{}{}
+		mu.Lock()
+		stop := abort != nil || (opts.StopOnError && failed)
+		mu.Unlock()
+		if stop || ctx.Err() != nil {
+			break
+		}
+		result.Chunks[i].Skipped = false
+		jobs <- &result.Chunks[i]
+	}
+	close(jobs)
+	wg.Wait()
+
+	if abort == nil {
+		abort = ctx.Err()
+	}
+	result.collect(ids)
+	return result, abort
+}
+
+// collect sums the chunk results into r. ids name the input rows, if known.
+func (r *BatchResult) collect(ids []string) {
+	for _, res := range r.Chunks {
+		r.Succeeded += res.Succeeded
+		r.Failed += res.Failed
+		r.RowIDs = append(r.RowIDs, res.RowIDs...)
+		if res.Skipped {
+			r.Skipped += res.Count
+		}
+		for i := 0; i < res.Failed; i++ {
+			f := RowFailure{Index: res.Start + i, Error: res.Error}
+			if ids != nil {
+				f.RowID = ids[f.Index]
+			}
+			r.Failures = append(r.Failures, f)
+		}
+	}
+}
+
+// chunkError returns the server message for API errors and the error text
+// otherwise.
+func chunkError(err error) string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Message != "" {
+		return apiErr.Message
+	}
+	return err.Error()
+}
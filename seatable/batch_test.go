@@ -0,0 +1,206 @@
+package seatable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// batchServer serves the batch row endpoints. Rows named in fail are
+// rejected with a 400 for the whole request.
+type batchServer struct {
+	fail map[string]bool
+
+	mu       sync.Mutex
+	requests int
+}
+
+func (s *batchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Rows   []Row    `json:"rows"`
+		RowIDs []string `json:"row_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	s.requests++
+	s.mu.Unlock()
+
+	names := body.RowIDs
+	for _, row := range body.Rows {
+		names = append(names, fmt.Sprint(row["Name"]))
+	}
+	for _, name := range names {
+		if s.fail[name] {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"error_msg":"bad row %s"}`, name)
+			return
+		}
+	}
+	// Hold back the first chunk so later chunks finish before it.
+	if len(names) > 0 && names[0] == "r0" {
+		time.Sleep(20 * time.Millisecond)
+	}
+	out := AppendRowsResult{InsertedRowCount: len(body.Rows)}
+	for _, name := range names {
+		out.RowIDs = append(out.RowIDs, Row{"_id": "id-" + name})
+	}
+	json.NewEncoder(w).Encode(out)
+}
+
+func testRows(n int) []Row {
+	rows := make([]Row, n)
+	for i := range rows {
+		rows[i] = Row{"Name": fmt.Sprintf("r%d", i)}
+	}
+	return rows
+}
+
+func TestAppendRowsChunked(t *testing.T) {
+	srv := &batchServer{fail: map[string]bool{"r7": true}}
+	c := newTestClient(t, srv)
+	c.Retry = RetryPolicy{MaxAttempts: 1}
+
+	res, err := c.AppendRowsChunked(context.Background(), "Orders", testRows(10), &BatchOptions{ChunkSize: 3, Parallelism: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if srv.requests != 4 {
+		t.Errorf("sent %d requests, want 4", srv.requests)
+	}
+	want := []ChunkResult{
+		{Index: 0, Start: 0, Count: 3, Succeeded: 3, StatusCode: 200, RowIDs: []string{"id-r0", "id-r1", "id-r2"}},
+		{Index: 1, Start: 3, Count: 3, Succeeded: 3, StatusCode: 200, RowIDs: []string{"id-r3", "id-r4", "id-r5"}},
+		{Index: 2, Start: 6, Count: 3, Failed: 3, StatusCode: 400, Error: "bad row r7"},
+		{Index: 3, Start: 9, Count: 1, Succeeded: 1, StatusCode: 200, RowIDs: []string{"id-r9"}},
+	}
+	if !reflect.DeepEqual(res.Chunks, want) {
+		t.Errorf("chunks = %+v, want %+v", res.Chunks, want)
+	}
+	wantIDs := []string{"id-r0", "id-r1", "id-r2", "id-r3", "id-r4", "id-r5", "id-r9"}
+	if !reflect.DeepEqual(res.RowIDs, wantIDs) {
+		t.Errorf("row ids = %v, want %v", res.RowIDs, wantIDs)
+	}
+	if res.Succeeded != 7 || res.Failed != 3 || res.Skipped != 0 || len(res.Failures) != 3 || res.Failures[0].Index != 6 {
+		t.Errorf("result = %+v", res)
+	}
+	if first := res.FirstError(); first == nil || first.Index != 2 {
+		t.Errorf("FirstError = %+v, want chunk 2", first)
+	}
+}
+
+func TestAppendRowsChunkedSize(t *testing.T) {
+	tests := []struct {
+		rows, size, chunks int
+	}{
+		{0, 0, 0},
+		{1000, 0, 1},
+		{2500, 0, 3},
+		{2500, 5000, 3},
+		{10, 4, 3},
+	}
+	for _, tt := range tests {
+		srv := &batchServer{}
+		c := newTestClient(t, srv)
+		res, err := c.AppendRowsChunked(context.Background(), "Orders", testRows(tt.rows), &BatchOptions{ChunkSize: tt.size})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Chunks) != tt.chunks || srv.requests != tt.chunks || res.Succeeded != tt.rows {
+			t.Errorf("%d rows, chunk size %d: %d chunks, %d requests, %d succeeded; want %d chunks", tt.rows, tt.size, len(res.Chunks), srv.requests, res.Succeeded, tt.chunks)
+		}
+	}
+}
+
+func TestDeleteRowsChunkedStopOnError(t *testing.T) {
+	ids := []string{"a", "b", "c", "bad", "e", "f", "g"}
+	tests := []struct {
+		name        string
+		stop        bool
+		requests    int
+		succeeded   int
+		skipped     int
+		skippedFrom int
+	}{
+		{"continues after a failure", false, 4, 5, 0, 4},
+		{"stops after a failure", true, 2, 2, 3, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &batchServer{fail: map[string]bool{"bad": true}}
+			c := newTestClient(t, srv)
+
+			res, err := c.DeleteRowsChunked(context.Background(), "Orders", ids, &BatchOptions{ChunkSize: 2, StopOnError: tt.stop})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if srv.requests != tt.requests {
+				t.Errorf("sent %d requests, want %d", srv.requests, tt.requests)
+			}
+			if res.Succeeded != tt.succeeded || res.Failed != 2 || res.Skipped != tt.skipped {
+				t.Errorf("succeeded %d, failed %d, skipped %d; want %d, 2, %d", res.Succeeded, res.Failed, res.Skipped, tt.succeeded, tt.skipped)
+			}
+			for i, chunk := range res.Chunks {
+				if chunk.Skipped != (i >= tt.skippedFrom) {
+					t.Errorf("chunk %d skipped = %v", i, chunk.Skipped)
+				}
+			}
+			wantFailures := []RowFailure{{Index: 2, RowID: "c", Error: "bad row bad"}, {Index: 3, RowID: "bad", Error: "bad row bad"}}
+			if !reflect.DeepEqual(res.Failures, wantFailures) {
+				t.Errorf("failures = %+v, want %+v", res.Failures, wantFailures)
+			}
+			if !reflect.DeepEqual(res.RowIDs[:2], []string{"a", "b"}) {
+				t.Errorf("row ids = %v", res.RowIDs)
+			}
+		})
+	}
+}
+
+func TestUpdateRowsChunkedParallel(t *testing.T) {
+	srv := &batchServer{}
+	c := newTestClient(t, srv)
+	updates := make([]RowUpdate, 25)
+	want := make([]string, len(updates))
+	for i := range updates {
+		want[i] = fmt.Sprintf("r%d", i)
+		updates[i] = RowUpdate{RowID: want[i], Row: Row{"Done": true}}
+	}
+
+	res, err := c.UpdateRowsChunked(context.Background(), "Orders", updates, &BatchOptions{ChunkSize: 4, Parallelism: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Chunks) != 7 || srv.requests != 7 || res.Succeeded != 25 {
+		t.Errorf("%d chunks, %d requests, %d succeeded", len(res.Chunks), srv.requests, res.Succeeded)
+	}
+	for i, chunk := range res.Chunks {
+		if chunk.Index != i || chunk.Start != 4*i {
+			t.Errorf("chunk %d is out of order: %+v", i, chunk)
+		}
+	}
+	if !reflect.DeepEqual(res.RowIDs, want) {
+		t.Errorf("row ids = %v, want input order", res.RowIDs)
+	}
+}
+
+func TestRunChunksCanceled(t *testing.T) {
+	srv := &batchServer{}
+	c := newTestClient(t, srv)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	res, err := c.DeleteRowsChunked(ctx, "Orders", []string{"a", "b", "c"}, &BatchOptions{ChunkSize: 1})
+	if err != context.Canceled {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if srv.requests != 0 || res.Skipped != 3 {
+		t.Errorf("sent %d requests, skipped %d rows; want 0 and 3", srv.requests, res.Skipped)
+	}
+}
@@ -0,0 +1,256 @@
+// Package seatable is a client for the SeaTable base API.
+//
+// A Client talks to one base. Create it with NewClient, authenticate it with
+// one of the Use*Token methods and call the typed methods such as ListRows,
+// AppendRows or QuerySQL:
+//
+//	c := seatable.NewClient("https://cloud.seatable.io", nil)
+//	if err := c.UseAPIToken(ctx, apiToken); err != nil {
+//		return err
+//	}
+//	rows, _, err := c.ListRows(ctx, "Contacts", &seatable.ListRowsOptions{Limit: 100})
+//
+// Every method returns the raw Response next to the decoded result. Non-2xx
+// responses are reported as *APIError; the Response is still returned so
+// callers can inspect the status and body.
+package seatable
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client holds the connection to a base. Base details are filled in once,
+// when the client is authenticated, and may be read without locking.
+type Client struct {
+	Server   string
+	BaseUUID string
+
+	DTableServer string
+	DTableDB     string
+
+	// APIFlavor selects gateway or legacy routes; see ResolveAPIFlavor.
+	APIFlavor     string
+	ServerVersion string
+
+	WorkspaceID int
+	BaseName    string
+
+	// Retry controls how transient failures are retried.
+	Retry RetryPolicy
+
+	// SchemaTTL is how long metadata is cached; 0 means DefaultSchemaTTL.
+	SchemaTTL time.Duration
+	schema    schemaCache
+
+	httpClient *http.Client
+	limiter    *tokenBucket
+
+	// token is the base access token. apiToken or accountToken is the
+	// long-lived credential it was obtained with; when both are empty, token
+	// is used as-is and never refreshed.
+	mu           sync.Mutex
+	token        secret
+	apiToken     secret
+	accountToken secret
+	expiresAt    time.Time
+	closed       bool
+}
+
+// Response is the raw answer to an API call.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// NewClient returns an unauthenticated client for server. A nil httpClient
+// uses a shared pooled client with default settings.
+func NewClient(server string, httpClient *http.Client) *Client {
+	return &Client{
+		Server:     TrimServerURL(server),
+		APIFlavor:  APIFlavorGateway,
+		Retry:      DefaultRetryPolicy(),
+		httpClient: httpClient,
+	}
+}
+
+// TrimServerURL normalizes a server URL by removing surrounding spaces and
+// trailing slashes.
+func TrimServerURL(s string) string {
+	s = strings.TrimSpace(s)
+	return strings.TrimRight(s, "/")
+}
+
+// SetRateLimit limits the client to perMinute requests per minute. The budget
+// is shared by every client of the same base; 0 disables limiting.
+func (c *Client) SetRateLimit(perMinute int) {
+	if perMinute <= 0 {
+		c.limiter = nil
+		return
+	}
+	c.limiter = baseRateLimiter(c.BaseUUID, perMinute)
+}
+
+// Close wipes the client's tokens. Later calls fail with ErrClientClosed.
+func (c *Client) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token.wipe()
+	c.apiToken.wipe()
+	c.accountToken.wipe()
+	c.expiresAt = time.Time{}
+	c.closed = true
+}
+
+// ExpiresAt returns when the current access token expires, or the zero time
+// when unknown.
+func (c *Client) ExpiresAt() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.expiresAt
+}
+
+// do performs an authenticated JSON request and decodes a 2xx response into
+// v when it is not nil. The access token is renewed when it nears expiry, and
+// once more if the server still answers 401. Transient failures are retried
+// according to the client's retry policy.
+func (c *Client) do(ctx context.Context, method, url string, body, v any) (*Response, error) {
+	policy := c.Retry
+	attempts := policy.MaxAttempts
+	if attempts <= 0 || !policy.allows(method) {
+		attempts = 1
+	}
+
+	var (
+		resp *Response
+		err  error
+	)
+	for attempt := 1; ; attempt++ {
+		resp, err = c.attempt(ctx, method, url, body)
+		if attempt >= attempts {
+			break
+		}
+		if err != nil {
+			if !isRetryableError(err) || ctx.Err() != nil {
+				break
+			}
+		} else if !isRetryableStatus(resp.StatusCode) {
+			break
+		}
+		var header http.Header
+		if resp != nil {
+			header = resp.Header
+		}
+		if err := sleepContext(ctx, policy.delay(attempt, header)); err != nil {
+			return resp, err
+		}
+	}
+	if err != nil {
+		return resp, err
+	}
+	return resp, decodeResponse(resp, v)
+}
+
+// attempt performs one authenticated request, renewing the access token once
+// on 401.
+func (c *Client) attempt(ctx context.Context, method, url string, body any) (*Response, error) {
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	token, err := c.AccessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := roundTrip(ctx, c.HTTPClient(), method, url, bearer(token), body)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || !c.CanRefresh() {
+		return resp, err
+	}
+
+	token, err = c.RenewAccessToken(ctx, token)
+	if err != nil {
+		return resp, err
+	}
+	return roundTrip(ctx, c.HTTPClient(), method, url, bearer(token), body)
+}
+
+func bearer(token string) string {
+	if strings.TrimSpace(token) == "" {
+		return ""
+	}
+	return "Bearer " + token
+}
+
+// send performs a request with an explicit Authorization header instead of
+// the base access token and decodes a 2xx response into v.
+func send(ctx context.Context, client *http.Client, method, url, auth string, body, v any) (*Response, error) {
+	resp, err := roundTrip(ctx, client, method, url, auth, body)
+	if err != nil {
+		return resp, err
+	}
+	return resp, decodeResponse(resp, v)
+}
+
+// decodeResponse returns an *APIError for a non-2xx response and otherwise
+// unmarshals the body into v.
+func decodeResponse(resp *Response, v any) error {
+	if err := CheckResponse(resp); err != nil {
+		return err
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Body, v); err != nil {
+		return fmt.Errorf("seatable: parse response: %w", err)
+	}
+	return nil
+}
+
+// roundTrip marshals body (if not nil) and performs a single HTTP request.
+func roundTrip(ctx context.Context, client *http.Client, method, url, auth string, body any) (*Response, error) {
+	var buf io.Reader = http.NoBody
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("marshal request body: %w", err)
+		}
+		buf = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, buf)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	out := &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: respBody}
+	if err != nil {
+		return out, fmt.Errorf("read response body: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,385 @@
+package seatable
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Column types that cannot be written.
+var readOnlyColumns = map[string]bool{
+	ColumnFormula:      true,
+	ColumnLinkFormula:  true,
+	ColumnCreator:      true,
+	ColumnLastModifier: true,
+	ColumnCTime:        true,
+	ColumnMTime:        true,
+	"auto-number":      true,
+	"button":           true,
+}
+
+// CoerceOptions configures a Coercer.
+type CoerceOptions struct {
+	// DateFormats are tried in order when parsing dates. They may be Go
+	// layouts or patterns such as "DD.MM.YYYY HH:mm"; see DateLayout. ISO
+	// dates are always accepted.
+	DateFormats []string
+	// DecimalComma parses "1.234,5" instead of "1,234.5".
+	DecimalComma bool
+	// Location is the time zone of dates without an offset; nil means UTC.
+	Location *time.Location
+}
+
+// FieldError reports a value that could not be coerced to its column type.
+type FieldError struct {
+	Column string `json:"column"`
+	Value  any    `json:"value"`
+	Reason string `json:"reason"`
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Column, e.Reason)
+}
+
+// CoerceError lists the fields of a row that could not be coerced.
+type CoerceError struct {
+	Fields []FieldError
+}
+
+func (e *CoerceError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return "seatable: invalid values: " + strings.Join(msgs, "; ")
+}
+
+// Coercer converts loosely typed input, typically strings from CSV files or
+// parsed emails, into the values SeaTable expects for each column type.
+type Coercer struct {
+	columns map[string]*Column
+	layouts []string
+	comma   bool
+	loc     *time.Location
+}
+
+// NewCoercer returns a coercer for rows keyed by column name or key.
+func NewCoercer(columns []Column, opts *CoerceOptions) *Coercer {
+	if opts == nil {
+		opts = &CoerceOptions{}
+	}
+	c := &Coercer{
+		columns: make(map[string]*Column, 2*len(columns)),
+		comma:   opts.DecimalComma,
+		loc:     opts.Location,
+	}
+	if c.loc == nil {
+		c.loc = time.UTC
+	}
+	for i := range columns {
+		col := &columns[i]
+		c.columns[col.Key] = col
+		c.columns[col.Name] = col
+	}
+	for _, f := range opts.DateFormats {
+		if f = strings.TrimSpace(f); f != "" {
+			c.layouts = append(c.layouts, DateLayout(f))
+		}
+	}
+	c.layouts = append(c.layouts, dateLayouts...)
+	return c
+}
+
+// TableCoercer returns a Coercer for the columns of table, read from the
+// schema cache.
+func (c *Client) TableCoercer(ctx context.Context, table string, opts *CoerceOptions) (*Coercer, error) {
+	t, err := c.ResolveTable(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	return NewCoercer(t.Columns, opts), nil
+}
+
+// Row returns a copy of row with every value coerced to its column type. All
+// failures are collected into a *CoerceError.
+func (c *Coercer) Row(row Row) (Row, error) {
+	out := make(Row, len(row))
+	var errs []FieldError
+	for k, v := range row {
+		col, ok := c.columns[k]
+		if !ok {
+			if strings.HasPrefix(k, "_") {
+				out[k] = v
+				continue
+			}
+			errs = append(errs, FieldError{Column: k, Value: v, Reason: "unknown column"})
+			continue
+		}
+		cv, err := c.Value(col, v)
+		if err != nil {
+			errs = append(errs, FieldError{Column: k, Value: v, Reason: err.Error()})
+			continue
+		}
+		out[k] = cv
+	}
+	if len(errs) > 0 {
+		return nil, &CoerceError{Fields: errs}
+	}
+	return out, nil
+}
+
+// Value coerces a single value for col. Empty strings and nil clear the cell.
+func (c *Coercer) Value(col *Column, v any) (any, error) {
+	if readOnlyColumns[col.Type] {
+		return nil, fmt.Errorf("column type %s is read-only", col.Type)
+	}
+	if s, ok := v.(string); v == nil || (ok && strings.TrimSpace(s) == "") {
+		if col.Type == ColumnCheckbox {
+			return false, nil
+		}
+		return nil, nil
+	}
+
+	switch col.Type {
+	case ColumnNumber:
+		return c.number(col, v)
+	case ColumnRating:
+		f, err := c.number(col, v)
+		if err != nil {
+			return nil, err
+		}
+		return int64(math.Round(f)), nil
+	case ColumnDuration:
+		return c.duration(v)
+	case ColumnDate:
+		return c.date(col, v)
+	case ColumnCheckbox:
+		if b, ok := parseBool(v); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("cannot parse %v as checkbox", v)
+	case ColumnSingleSelect:
+		s, ok := v.(string)
+		if !ok {
+			s = fmt.Sprint(v)
+		}
+		return c.option(col, s)
+	case ColumnMultipleSelect:
+		items := listValue(v)
+		names := make([]string, 0, len(items))
+		for _, item := range items {
+			name, err := c.option(col, item)
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, name)
+		}
+		return names, nil
+	case ColumnCollaborator:
+		return listValue(v), nil
+	case ColumnText, ColumnLongText, "email", "url":
+		return textValue(v), nil
+	}
+	return v, nil
+}
+
+func (c *Coercer) number(col *Column, v any) (float64, error) {
+	switch t := v.(type) {
+	case float64:
+		return t, nil
+	case int:
+		return float64(t), nil
+	case int64:
+		return float64(t), nil
+	case string:
+		s := strings.TrimSpace(t)
+		percent := strings.HasSuffix(s, "%")
+		s = strings.TrimSuffix(s, "%")
+		s = strings.TrimLeft(s, "$€£¥ ")
+		s = strings.ReplaceAll(s, " ", "")
+		sep, point := byte(','), byte('.')
+		if c.comma {
+			sep, point = point, sep
+		}
+		s, ok := ungroup(s, sep, point)
+		if c.comma {
+			s = strings.Replace(s, ",", ".", 1)
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if !ok || err != nil {
+			return 0, fmt.Errorf("cannot parse %q as number", t)
+		}
+		if percent {
+			f /= 100
+		}
+		if integerFormat(col) {
+			f = math.Round(f)
+		}
+		return f, nil
+	}
+	return 0, fmt.Errorf("cannot use %T as number", v)
+}
+
+// ungroup removes the thousands separator sep from the integer part of s. It
+// reports false when sep appears anywhere but between groups of three digits,
+// so "1,5" is rejected instead of read as 15.
+func ungroup(s string, sep, point byte) (string, bool) {
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, point); i >= 0 {
+		whole, frac = s[:i], s[i:]
+	}
+	if strings.IndexByte(frac, sep) >= 0 {
+		return "", false
+	}
+	if strings.IndexByte(whole, sep) < 0 {
+		return s, true
+	}
+	groups := strings.Split(strings.TrimLeft(whole, "+-"), string(sep))
+	for i, g := range groups {
+		if len(g) == 0 || len(g) > 3 || (i > 0 && len(g) != 3) {
+			return "", false
+		}
+		for j := 0; j < len(g); j++ {
+			if g[j] < '0' || g[j] > '9' {
+				return "", false
+			}
+		}
+	}
+	return strings.ReplaceAll(whole, string(sep), "") + frac, true
+}
+
+// duration accepts seconds or h:mm[:ss] and returns seconds.
+func (c *Coercer) duration(v any) (int64, error) {
+	if s, ok := v.(string); ok && strings.Contains(s, ":") {
+		parts := strings.Split(strings.TrimSpace(s), ":")
+		if len(parts) > 3 {
+			return 0, fmt.Errorf("cannot parse %q as duration", s)
+		}
+		var secs int64
+		for _, p := range parts {
+			n, err := strconv.ParseInt(p, 10, 64)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("cannot parse %q as duration", s)
+			}
+			secs = secs*60 + n
+		}
+		if len(parts) == 2 {
+			secs *= 60
+		}
+		return secs, nil
+	}
+	f, err := c.number(&Column{}, v)
+	if err != nil {
+		return 0, err
+	}
+	return int64(math.Round(f)), nil
+}
+
+// date parses v and formats it the way the column stores dates.
+func (c *Coercer) date(col *Column, v any) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("cannot use %T as date", v)
+	}
+	s = strings.TrimSpace(s)
+	for _, layout := range c.layouts {
+		t, err := time.ParseInLocation(layout, s, c.loc)
+		if err != nil {
+			continue
+		}
+		format, _ := col.Data["format"].(string)
+		if strings.Contains(format, "HH") {
+			return t.Format("2006-01-02 15:04"), nil
+		}
+		return t.Format("2006-01-02"), nil
+	}
+	return "", fmt.Errorf("cannot parse %q as date", s)
+}
+
+// option maps a select option name or id to the option name. Names are
+// matched case-insensitively.
+func (c *Coercer) option(col *Column, v string) (string, error) {
+	v = strings.TrimSpace(v)
+	opts := options(col)
+	names := make([]string, 0, len(opts))
+	for _, o := range opts {
+		id, _ := o["id"].(string)
+		name, _ := o["name"].(string)
+		if id == v || strings.EqualFold(name, v) {
+			return name, nil
+		}
+		names = append(names, name)
+	}
+	return "", fmt.Errorf("%q is not an option (have %s)", v, strings.Join(names, ", "))
+}
+
+// listValue splits a comma-separated string or converts a JSON array to
+// strings.
+func listValue(v any) []string {
+	var out []string
+	switch t := v.(type) {
+	case []any:
+		for _, item := range t {
+			if s := strings.TrimSpace(textValue(item)); s != "" {
+				out = append(out, s)
+			}
+		}
+	case []string:
+		out = t
+	default:
+		for _, p := range strings.Split(textValue(v), ",") {
+			if s := strings.TrimSpace(p); s != "" {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+func textValue(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// dateTokens maps pattern tokens to Go layout elements, longest first.
+var dateTokens = []struct{ token, layout string }{
+	{"YYYY", "2006"}, {"YY", "06"},
+	{"MMMM", "January"}, {"MMM", "Jan"}, {"MM", "01"}, {"M", "1"},
+	{"DD", "02"}, {"D", "2"},
+	{"HH", "15"}, {"hh", "03"}, {"h", "3"},
+	{"mm", "04"}, {"ss", "05"}, {"A", "PM"}, {"a", "pm"},
+}
+
+// DateLayout converts a pattern such as "DD.MM.YYYY HH:mm" into a Go time
+// layout. Formats without any pattern token are returned unchanged, so Go
+// layouts may be used directly.
+func DateLayout(format string) string {
+	if !strings.Contains(format, "YY") && !strings.Contains(format, "DD") {
+		return format
+	}
+	var b strings.Builder
+	for i := 0; i < len(format); {
+		matched := false
+		for _, t := range dateTokens {
+			if strings.HasPrefix(format[i:], t.token) {
+				b.WriteString(t.layout)
+				i += len(t.token)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			b.WriteByte(format[i])
+			i++
+		}
+	}
+	return b.String()
+}
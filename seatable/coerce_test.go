@@ -0,0 +1,107 @@
+package seatable
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestCoerceNumber(t *testing.T) {
+	tests := []struct {
+		in    string
+		comma bool
+		want  float64
+		ok    bool
+	}{
+		{"1234.5", false, 1234.5, true},
+		{"1,234.5", false, 1234.5, true},
+		{"-1,234,567", false, -1234567, true},
+		{"$ 1,000", false, 1000, true},
+		{"12.5%", false, 0.125, true},
+		{"1 234", false, 1234, true},
+		{"1,5", false, 0, false},
+		{"12,34", false, 0, false},
+		{"1,2345", false, 0, false},
+		{",123", false, 0, false},
+		{"1.234,5", false, 0, false},
+		{"1.5,000", false, 0, false},
+		{"1.234,5", true, 1234.5, true},
+		{"1,5", true, 1.5, true},
+		{"€1.000.000", true, 1000000, true},
+		{"1.5", true, 0, false},
+		{"1,234.5", true, 0, false},
+		{"abc", false, 0, false},
+	}
+	for _, tt := range tests {
+		c := NewCoercer(nil, &CoerceOptions{DecimalComma: tt.comma})
+		got, err := c.number(&Column{}, tt.in)
+		if (err == nil) != tt.ok {
+			t.Errorf("number(%q, comma=%v) error = %v, want ok %v", tt.in, tt.comma, err, tt.ok)
+			continue
+		}
+		if tt.ok && got != tt.want {
+			t.Errorf("number(%q, comma=%v) = %v, want %v", tt.in, tt.comma, got, tt.want)
+		}
+	}
+}
+
+func TestCoerceRow(t *testing.T) {
+	columns := []Column{
+		{Key: "0000", Name: "Name", Type: ColumnText},
+		{Key: "0001", Name: "Amount", Type: ColumnNumber, Data: map[string]any{"enable_precision": true, "precision": float64(0)}},
+		{Key: "0002", Name: "Paid", Type: ColumnCheckbox},
+		{Key: "0003", Name: "Due", Type: ColumnDate},
+		{Key: "0004", Name: "Status", Type: ColumnSingleSelect, Data: map[string]any{"options": []any{
+			map[string]any{"id": "a1", "name": "Open"},
+			map[string]any{"id": "b2", "name": "Done"},
+		}}},
+		{Key: "0005", Name: "Total", Type: ColumnFormula},
+		{Key: "0006", Name: "Time", Type: ColumnDuration},
+	}
+	c := NewCoercer(columns, &CoerceOptions{DateFormats: []string{"DD.MM.YYYY"}})
+
+	got, err := c.Row(Row{
+		"Name":   42.0,
+		"0001":   "1,234.6",
+		"Paid":   "yes",
+		"Due":    "31.01.2024",
+		"Status": "done",
+		"Time":   "1:30",
+		"_id":    "r1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Row{
+		"Name":   "42",
+		"0001":   float64(1235),
+		"Paid":   true,
+		"Due":    "2024-01-31",
+		"Status": "Done",
+		"Time":   int64(5400),
+		"_id":    "r1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Row = %v, want %v", got, want)
+	}
+
+	_, err = c.Row(Row{
+		"Name":   "ok",
+		"Amount": "1,5",
+		"Status": "Closed",
+		"Total":  "1",
+		"Extra":  "x",
+	})
+	cerr, ok := err.(*CoerceError)
+	if !ok {
+		t.Fatalf("err = %v, want *CoerceError", err)
+	}
+	var fields []string
+	for _, f := range cerr.Fields {
+		fields = append(fields, f.Column)
+	}
+	sort.Strings(fields)
+	if want := []string{"Amount", "Extra", "Status", "Total"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("failed fields = %v, want %v", fields, want)
+	}
+}
@@ -0,0 +1,83 @@
+package seatable
+
+import (
+	"context"
+	"sync"
+)
+
+// CursorOptions configures OpenCursor.
+type CursorOptions struct {
+	// View limits the rows to a view. It cannot be combined with Filter.
+	View        string
+	Filter      *Filter
+	Start       int
+	PageSize    int
+	ConvertKeys bool
+}
+
+// Cursor reads the rows of a table page by page. Only the position is kept,
+// so memory use does not grow with the table. A Cursor is safe for
+// concurrent use; pages are handed out in order.
+type Cursor struct {
+	client *Client
+	table  string
+	opts   CursorOptions
+
+	mu     sync.Mutex
+	offset int
+	done   bool
+}
+
+// OpenCursor returns a cursor positioned at opts.Start. No request is made
+// until the first call to Next.
+func (c *Client) OpenCursor(table string, opts *CursorOptions) *Cursor {
+	cur := &Cursor{client: c, table: table}
+	if opts != nil {
+		cur.opts = *opts
+	}
+	if cur.opts.PageSize <= 0 {
+		cur.opts.PageSize = MaxPageSize
+	}
+	cur.offset = max(cur.opts.Start, 0)
+	return cur
+}
+
+// Next returns the next n rows, or the cursor's page size when n is not
+// positive, and whether more rows may follow. A failed call does not move
+// the cursor, so it can be retried; an *APIError always comes with a
+// Response.
+func (cur *Cursor) Next(ctx context.Context, n int) ([]Row, bool, *Response, error) {
+	cur.mu.Lock()
+	defer cur.mu.Unlock()
+
+	if cur.done {
+		return []Row{}, false, &Response{StatusCode: 200}, nil
+	}
+	if n <= 0 {
+		n = cur.opts.PageSize
+	}
+	rows, resp, err := cur.client.FetchRows(ctx, cur.table, &FetchRowsOptions{
+		View:        cur.opts.View,
+		Filter:      cur.opts.Filter,
+		Start:       cur.offset,
+		MaxRows:     n,
+		PageSize:    min(n, MaxPageSize),
+		ConvertKeys: cur.opts.ConvertKeys,
+	})
+	if err != nil {
+		if resp == nil {
+			resp = errorResponse(err)
+		}
+		return nil, false, resp, err
+	}
+	cur.offset += len(rows)
+	cur.done = len(rows) < n
+	return rows, !cur.done, resp, nil
+}
+
+// Offset returns the position of the next row to read.
+func (cur *Cursor) Offset() int {
+	cur.mu.Lock()
+	defer cur.mu.Unlock()
+	return cur.offset
+}
@@ -0,0 +1,62 @@
+package seatable
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCursorNext(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	orders := ordersServer(2500)
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			http.Error(w, `{"error_msg": "metadata unavailable"}`, http.StatusInternalServerError)
+			return
+		}
+		orders.ServeHTTP(w, r)
+	}))
+	cur := c.OpenCursor("Orders", &CursorOptions{
+		Filter:   &Filter{Column: "Name", Operator: "is_not_empty"},
+		PageSize: 1000,
+	})
+
+	_, more, resp, err := cur.Next(context.Background(), 0)
+	if !IsStatus(err, http.StatusInternalServerError) {
+		t.Fatalf("err = %v, want a 500 APIError", err)
+	}
+	if resp == nil || resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("resp = %+v, want status 500", resp)
+	}
+	if more || cur.Offset() != 0 {
+		t.Errorf("failed call moved the cursor: more = %v, offset = %d", more, cur.Offset())
+	}
+
+	failing.Store(false)
+	var sizes []int
+	offset := 0
+	for {
+		rows, more, resp, err := cur.Next(context.Background(), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp == nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("resp = %+v, want status 200", resp)
+		}
+		if len(rows) > 0 && rows[0].ID() != fmt.Sprintf("r%d", offset) {
+			t.Errorf("page %d starts at %s, want r%d", len(sizes), rows[0].ID(), offset)
+		}
+		sizes = append(sizes, len(rows))
+		offset += len(rows)
+		if !more {
+			break
+		}
+	}
+	if want := []int{1000, 1000, 500}; !reflect.DeepEqual(sizes, want) {
+		t.Errorf("page sizes = %v, want %v", sizes, want)
+	}
+}
@@ -0,0 +1,381 @@
+package seatable
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Column types with a normalized decoded shape.
+const (
+	ColumnText           = "text"
+	ColumnLongText       = "long-text"
+	ColumnNumber         = "number"
+	ColumnCheckbox       = "checkbox"
+	ColumnDate           = "date"
+	ColumnDuration       = "duration"
+	ColumnRating         = "rating"
+	ColumnSingleSelect   = "single-select"
+	ColumnMultipleSelect = "multiple-select"
+	ColumnCollaborator   = "collaborator"
+	ColumnCreator        = "creator"
+	ColumnLastModifier   = "last-modifier"
+	ColumnCTime          = "ctime"
+	ColumnMTime          = "mtime"
+	ColumnLink           = "link"
+	ColumnFile           = "file"
+	ColumnImage          = "image"
+	ColumnGeolocation    = "geolocation"
+	ColumnFormula        = "formula"
+	ColumnLinkFormula    = "link-formula"
+)
+
+// dateLayouts are the formats SeaTable uses for date values, most specific
+// first. Values without an offset are taken as UTC.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000-07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// LinkValue is the decoded shape of a linked row.
+type LinkValue struct {
+	ID      string `json:"id"`
+	Display any    `json:"display"`
+}
+
+// Decoder normalizes row values using the column types of a table:
+//
+//   - date, ctime and mtime: RFC3339 strings
+//   - number: float64, or int64 when the column shows no decimals
+//   - duration and rating: int64
+//   - checkbox: bool
+//   - single-select: the option name; multiple-select: []string of names
+//   - collaborator: []string of user ids; creator and last-modifier: the
+//     user id. With WithUsers, the ids are replaced by contact emails.
+//   - link: []LinkValue
+//   - file: []Attachment; image: []string of URLs
+//   - geolocation: map with float64 lat and lng
+//
+// Formula columns are decoded according to their result type. Other columns
+// and values that do not match the expected shape are left unchanged.
+type Decoder struct {
+	columns map[string]*Column
+	users   map[string]string
+}
+
+// NewDecoder returns a decoder for rows keyed by column name or key.
+func NewDecoder(columns []Column) *Decoder {
+	d := &Decoder{columns: make(map[string]*Column, 2*len(columns)+2)}
+	for i := range columns {
+		col := &columns[i]
+		d.columns[col.Key] = col
+		d.columns[col.Name] = col
+	}
+	// System timestamps are not listed among the columns.
+	d.columns["_ctime"] = &Column{Key: "_ctime", Name: "_ctime", Type: ColumnCTime}
+	d.columns["_mtime"] = &Column{Key: "_mtime", Name: "_mtime", Type: ColumnMTime}
+	return d
+}
+
+// WithUsers makes d map user ids to the emails in emails, as returned by
+// UserEmails. Unknown ids are kept.
+func (d *Decoder) WithUsers(emails map[string]string) *Decoder {
+	d.users = emails
+	return d
+}
+
+// TableDecoder returns a Decoder for the columns of table, read from the
+// schema cache. User ids are mapped to contact emails; see ColumnDecoder.
+func (c *Client) TableDecoder(ctx context.Context, table string) (*Decoder, error) {
+	t, err := c.ResolveTable(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	return c.ColumnDecoder(ctx, t.Columns)
+}
+
+// Rows decodes every row in place and returns rows.
+func (d *Decoder) Rows(rows []Row) []Row {
+	for _, row := range rows {
+		d.Row(row)
+	}
+	return rows
+}
+
+// Row decodes the values of row in place and returns it.
+func (d *Decoder) Row(row Row) Row {
+	for k, v := range row {
+		if col, ok := d.columns[k]; ok {
+			row[k] = DecodeValue(col, v)
+			if d.users != nil && isUserColumn(col) {
+				row[k] = d.userEmails(row[k])
+			}
+		}
+	}
+	return row
+}
+
+// DecodeValue normalizes a single value of col; see Decoder.
+func DecodeValue(col *Column, v any) any {
+	if v == nil {
+		return nil
+	}
+	switch col.Type {
+	case ColumnDate, ColumnCTime, ColumnMTime:
+		return decodeDate(v)
+	case ColumnNumber:
+		return decodeNumber(v, integerFormat(col))
+	case ColumnDuration, ColumnRating:
+		return decodeNumber(v, true)
+	case ColumnCheckbox:
+		if b, ok := parseBool(v); ok {
+			return b
+		}
+	case ColumnSingleSelect:
+		if s, ok := v.(string); ok {
+			return optionName(col, s)
+		}
+	case ColumnMultipleSelect:
+		if items, ok := v.([]any); ok {
+			names := make([]string, 0, len(items))
+			for _, item := range items {
+				if s, ok := item.(string); ok {
+					names = append(names, optionName(col, s))
+				}
+			}
+			return names
+		}
+	case ColumnCollaborator:
+		return decodeStrings(v)
+	case ColumnCreator, ColumnLastModifier:
+		if s, ok := v.(string); ok {
+			return s
+		}
+	case ColumnLink:
+		return decodeLinks(v)
+	case ColumnFile:
+		return decodeFiles(v)
+	case ColumnImage:
+		return decodeStrings(v)
+	case ColumnGeolocation:
+		return decodeGeolocation(v)
+	case ColumnFormula, ColumnLinkFormula:
+		return decodeFormula(col, v)
+	}
+	return v
+}
+
+// userEmails maps the user ids in a decoded user column value to emails.
+func (d *Decoder) userEmails(v any) any {
+	switch t := v.(type) {
+	case string:
+		if email, ok := d.users[t]; ok {
+			return email
+		}
+	case []string:
+		for i, id := range t {
+			if email, ok := d.users[id]; ok {
+				t[i] = email
+			}
+		}
+	}
+	return v
+}
+
+func decodeDate(v any) any {
+	s, ok := v.(string)
+	if !ok || strings.TrimSpace(s) == "" {
+		return v
+	}
+	if t, ok := parseDate(s); ok {
+		return t.Format(time.RFC3339)
+	}
+	return v
+}
+
+func parseDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.UTC); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// integerFormat reports whether a number column is shown without decimals.
+func integerFormat(col *Column) bool {
+	enabled, _ := col.Data["enable_precision"].(bool)
+	precision, ok := col.Data["precision"].(float64)
+	return enabled && ok && precision == 0
+}
+
+func decodeNumber(v any, integer bool) any {
+	var f float64
+	switch t := v.(type) {
+	case float64:
+		f = t
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(t), 64)
+		if err != nil {
+			return v
+		}
+		f = parsed
+	default:
+		return v
+	}
+	if integer {
+		return int64(math.Round(f))
+	}
+	return f
+}
+
+func parseBool(v any) (bool, bool) {
+	switch t := v.(type) {
+	case bool:
+		return t, true
+	case float64:
+		return t != 0, true
+	case string:
+		switch strings.ToLower(strings.TrimSpace(t)) {
+		case "true", "yes", "y", "1", "on":
+			return true, true
+		case "false", "no", "n", "0", "off", "":
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// options returns the select options of col.
+func options(col *Column) []map[string]any {
+	raw, _ := col.Data["options"].([]any)
+	out := make([]map[string]any, 0, len(raw))
+	for _, o := range raw {
+		if m, ok := o.(map[string]any); ok {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// optionName maps a select option id to its name. Names are returned as-is.
+func optionName(col *Column, v string) string {
+	for _, o := range options(col) {
+		if id, _ := o["id"].(string); id == v {
+			if name, ok := o["name"].(string); ok {
+				return name
+			}
+		}
+	}
+	return v
+}
+
+func decodeStrings(v any) any {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []any:
+		out := make([]string, 0, len(t))
+		for _, item := range t {
+			switch s := item.(type) {
+			case string:
+				out = append(out, s)
+			case map[string]any:
+				// Collaborators may come as {"email": ...}.
+				if email, ok := s["email"].(string); ok {
+					out = append(out, email)
+				}
+			}
+		}
+		return out
+	}
+	return v
+}
+
+func decodeLinks(v any) any {
+	items, ok := v.([]any)
+	if !ok {
+		return v
+	}
+	out := make([]LinkValue, 0, len(items))
+	for _, item := range items {
+		switch t := item.(type) {
+		case string:
+			out = append(out, LinkValue{ID: t})
+		case map[string]any:
+			id, _ := t["row_id"].(string)
+			if id == "" {
+				id, _ = t["_id"].(string)
+			}
+			display := t["display_value"]
+			if display == nil {
+				display = t["display"]
+			}
+			out = append(out, LinkValue{ID: id, Display: display})
+		}
+	}
+	return out
+}
+
+func decodeFiles(v any) any {
+	items, ok := v.([]any)
+	if !ok {
+		return v
+	}
+	out := make([]Attachment, 0, len(items))
+	for _, item := range items {
+		m, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		a := Attachment{}
+		a.Name, _ = m["name"].(string)
+		a.Type, _ = m["type"].(string)
+		a.URL, _ = m["url"].(string)
+		if size, ok := m["size"].(float64); ok {
+			a.Size = int64(size)
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+func decodeGeolocation(v any) any {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+	out := make(map[string]any, len(m))
+	for k, val := range m {
+		out[k] = val
+	}
+	for _, k := range []string{"lat", "lng"} {
+		if f, ok := decodeNumber(m[k], false).(float64); ok {
+			out[k] = f
+		}
+	}
+	return out
+}
+
+// decodeFormula decodes a formula value according to its result type.
+func decodeFormula(col *Column, v any) any {
+	resultType, _ := col.Data["result_type"].(string)
+	switch resultType {
+	case "date":
+		return decodeDate(v)
+	case "number":
+		return decodeNumber(v, integerFormat(col))
+	case "bool":
+		if b, ok := parseBool(v); ok {
+			return b
+		}
+	}
+	return v
+}
@@ -0,0 +1,143 @@
+package seatable
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// API flavors a client can talk to.
+const (
+	APIFlavorAuto    = "auto"
+	APIFlavorGateway = "gateway"
+	APIFlavorLegacy  = "legacy"
+)
+
+// Base resources addressed through the endpoint resolver.
+const (
+	endpointRows     = "rows"
+	endpointRow      = "row"
+	endpointSQL      = "sql"
+	endpointLinks    = "links"
+	endpointMetadata = "metadata"
+	endpointColumns  = "columns"
+	endpointViews    = "views"
+
+	endpointRelatedUsers = "related-users"
+
+	endpointBatchAppendRows = "batch-append-rows"
+	endpointBatchUpdateRows = "batch-update-rows"
+	endpointBatchDeleteRows = "batch-delete-rows"
+)
+
+// gatewayMinVersion is the first server release exposing the API gateway.
+var gatewayMinVersion = [3]int{4, 3, 0}
+
+// endpoint returns the URL of a base resource for the client's API flavor.
+// The row resource takes the row id as its only argument.
+//
+// The API gateway serves everything under /api-gateway/api/v2/dtables/. Older
+// servers split the same routes between dtable-server and dtable-db.
+func (c *Client) endpoint(resource string, args ...string) string {
+	return c.endpointFor(c.APIFlavor, resource, args...)
+}
+
+// endpointFor is endpoint with an explicit API flavor.
+func (c *Client) endpointFor(flavor, resource string, args ...string) string {
+	path := resource + "/"
+	if resource == endpointRow && len(args) > 0 {
+		path = "rows/" + url.PathEscape(args[0]) + "/"
+	}
+
+	if flavor != APIFlavorLegacy {
+		// The gateway handles one or many rows through the same route.
+		switch resource {
+		case endpointBatchAppendRows, endpointBatchUpdateRows, endpointBatchDeleteRows:
+			path = endpointRows + "/"
+		}
+		return fmt.Sprintf("%s/api-gateway/api/v2/dtables/%s/%s", c.Server, c.BaseUUID, path)
+	}
+
+	if resource == endpointSQL {
+		return fmt.Sprintf("%s/api/v1/query/%s/", c.dtableDB(), c.BaseUUID)
+	}
+	return fmt.Sprintf("%s/api/v1/dtables/%s/%s", c.dtableServer(), c.BaseUUID, path)
+}
+
+func (c *Client) dtableServer() string {
+	if c.DTableServer != "" {
+		return c.DTableServer
+	}
+	return c.Server + "/dtable-server"
+}
+
+func (c *Client) dtableDB() string {
+	if c.DTableDB != "" {
+		return c.DTableDB
+	}
+	return c.Server + "/dtable-db"
+}
+
+// ServerInfo is returned by /server-info/.
+type ServerInfo struct {
+	Version string `json:"version"`
+	Edition string `json:"edition"`
+}
+
+// GetServerInfo reads the server version. The endpoint needs no auth.
+func (c *Client) GetServerInfo(ctx context.Context) (*ServerInfo, *Response, error) {
+	url := fmt.Sprintf("%s/server-info/", c.Server)
+	var out ServerInfo
+	resp, err := send(ctx, c.HTTPClient(), "GET", url, "", nil, &out)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &out, resp, nil
+}
+
+// flavorForVersion picks the API flavor a server version supports. Unknown
+// versions are assumed to be recent.
+func flavorForVersion(version string) string {
+	parts := strings.SplitN(strings.TrimSpace(version), ".", 4)
+	var v [3]int
+	for i := 0; i < len(v) && i < len(parts); i++ {
+		n, err := strconv.Atoi(strings.TrimFunc(parts[i], func(r rune) bool { return r < '0' || r > '9' }))
+		if err != nil {
+			return APIFlavorGateway
+		}
+		v[i] = n
+	}
+	for i := range v {
+		if v[i] != gatewayMinVersion[i] {
+			if v[i] < gatewayMinVersion[i] {
+				return APIFlavorLegacy
+			}
+			return APIFlavorGateway
+		}
+	}
+	return APIFlavorGateway
+}
+
+// ResolveAPIFlavor sets the client's API flavor, detecting it from the server
+// version when flavor is auto or empty.
+func (c *Client) ResolveAPIFlavor(ctx context.Context, flavor string) {
+	switch flavor {
+	case APIFlavorGateway, APIFlavorLegacy:
+		c.APIFlavor = flavor
+		return
+	}
+	c.APIFlavor = APIFlavorGateway
+	if info, _, err := c.GetServerInfo(ctx); err == nil {
+		c.ServerVersion = info.Version
+		c.APIFlavor = flavorForVersion(info.Version)
+	}
+}
+
+// GatewayReachable reports whether the base answers on the API gateway, even
+// when the client uses legacy routes.
+func (c *Client) GatewayReachable(ctx context.Context) bool {
+	resp, err := c.do(ctx, "GET", c.endpointFor(APIFlavorGateway, endpointMetadata), nil, nil)
+	return err == nil && resp.StatusCode < 300
+}
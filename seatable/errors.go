@@ -0,0 +1,74 @@
+package seatable
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError reports a non-2xx response from SeaTable.
+type APIError struct {
+	StatusCode int
+	Message    string
+	Body       []byte
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("seatable: %d: %s", e.StatusCode, e.Message)
+}
+
+// CheckResponse returns an *APIError when resp has a non-2xx status.
+func CheckResponse(resp *Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	msg := ErrorMessage(resp.Body)
+	if msg == "" {
+		msg = http.StatusText(resp.StatusCode)
+	}
+	return &APIError{StatusCode: resp.StatusCode, Message: msg, Body: resp.Body}
+}
+
+// errorResponse returns the Response an *APIError was built from, so calls
+// that fail on a lookup before their own request, such as resolving the
+// table, still return the failing status. Other errors return nil.
+func errorResponse(err error) *Response {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return nil
+	}
+	return &Response{StatusCode: apiErr.StatusCode, Body: apiErr.Body}
+}
+
+// IsStatus reports whether err is an *APIError with the given status.
+func IsStatus(err error, status int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
+
+// ErrorMessage extracts the server message from an error body. SeaTable uses
+// different keys depending on the service that produced the error.
+func ErrorMessage(body []byte) string {
+	var parsed map[string]any
+	if err := json.Unmarshal(body, &parsed); err == nil {
+		for _, key := range []string{"error_msg", "error_message", "detail", "error", "message"} {
+			switch v := parsed[key].(type) {
+			case string:
+				if strings.TrimSpace(v) != "" {
+					return strings.TrimSpace(v)
+				}
+			case nil:
+			default:
+				b, _ := json.Marshal(v)
+				return string(b)
+			}
+		}
+	}
+	msg := strings.TrimSpace(string(body))
+	if len(msg) > 500 {
+		msg = msg[:500] + "…"
+	}
+	return msg
+}
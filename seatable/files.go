@@ -0,0 +1,196 @@
+package seatable
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// UploadLink is a one-time target for file uploads into the base's assets.
+type UploadLink struct {
+	UploadLink        string `json:"upload_link"`
+	ParentPath        string `json:"parent_path"`
+	FileRelativePath  string `json:"file_relative_path"`
+	ImageRelativePath string `json:"image_relative_path"`
+}
+
+// RelativePath returns where an upload of the given kind ("file" or "image")
+// is stored, relative to the base's asset directory.
+func (l *UploadLink) RelativePath(kind string) string {
+	if kind == "image" && l.ImageRelativePath != "" {
+		return l.ImageRelativePath
+	}
+	return l.FileRelativePath
+}
+
+// GetUploadLink requests a new upload link.
+func (c *Client) GetUploadLink(ctx context.Context) (*UploadLink, *Response, error) {
+	url := fmt.Sprintf("%s/api/v2.1/dtable/app-upload-link/", c.Server)
+	var out UploadLink
+	resp, err := c.do(ctx, "GET", url, nil, &out)
+	if err != nil {
+		return nil, resp, err
+	}
+	if out.UploadLink == "" {
+		return nil, resp, fmt.Errorf("upload_link is empty")
+	}
+	return &out, resp, nil
+}
+
+// Upload stores the content of r as name and returns the attachment to put in
+// a file or image column. kind is "file" or "image".
+func (c *Client) Upload(ctx context.Context, r io.Reader, name, kind string) (*Attachment, *Response, error) {
+	link, resp, err := c.GetUploadLink(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+	return c.UploadWithLink(ctx, link, r, name, kind)
+}
+
+// UploadFile uploads a local file. An empty name keeps the file's base name.
+func (c *Client) UploadFile(ctx context.Context, path, name, kind string) (*Attachment, *Response, error) {
+	link, resp, err := c.GetUploadLink(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+	return c.UploadFileWithLink(ctx, link, path, name, kind)
+}
+
+// UploadFileWithLink uploads a local file through an existing upload link.
+func (c *Client) UploadFileWithLink(ctx context.Context, link *UploadLink, path, name, kind string) (*Attachment, *Response, error) {
+	if name == "" {
+		name = filepath.Base(path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open file: %w", err)
+	}
+	defer f.Close()
+	return c.UploadWithLink(ctx, link, f, name, kind)
+}
+
+// UploadWithLink uploads the content of r through an existing upload link.
+// The attachment URL includes the workspace id. Clients authenticated with a
+// base access token do not know it, so their attachments come back without
+// a URL rather than with one that does not resolve.
+func (c *Client) UploadWithLink(ctx context.Context, link *UploadLink, r io.Reader, name, kind string) (*Attachment, *Response, error) {
+	if kind == "" {
+		kind = "file"
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fw, err := mw.CreateFormFile("file", name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := io.Copy(fw, r); err != nil {
+		return nil, nil, err
+	}
+	if err := mw.WriteField("parent_dir", link.ParentPath); err != nil {
+		return nil, nil, err
+	}
+	if err := mw.WriteField("relative_path", link.RelativePath(kind)); err != nil {
+		return nil, nil, err
+	}
+	if err := mw.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	uploadURL := fmt.Sprintf("%s/seafhttp/upload-api/%s?ret-json=1", c.Server, link.UploadLink)
+	req, err := http.NewRequestWithContext(ctx, "POST", uploadURL, &buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	httpResp, err := c.transferClient(uploadRequestTimeout).Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	resp := &Response{StatusCode: httpResp.StatusCode, Header: httpResp.Header, Body: respBody}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var files []struct {
+		Name string `json:"name"`
+		Size int64  `json:"size"`
+	}
+	if err := decodeResponse(resp, &files); err != nil {
+		return nil, resp, err
+	}
+	if len(files) == 0 {
+		return nil, resp, fmt.Errorf("no attachment returned")
+	}
+
+	return &Attachment{
+		Name: files[0].Name,
+		Size: files[0].Size,
+		Type: kind,
+		URL:  c.assetURL(link.RelativePath(kind), files[0].Name),
+	}, resp, nil
+}
+
+// assetURL returns the URL under which an uploaded asset is served, or ""
+// when the workspace is unknown.
+func (c *Client) assetURL(relativePath, name string) string {
+	if c.WorkspaceID == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s/workspace/%d/asset/%s/%s/%s",
+		c.Server, c.WorkspaceID, c.BaseUUID,
+		strings.Trim(relativePath, "/"), url.PathEscape(name))
+}
+
+// GetDownloadLink returns a temporary download URL for an asset path.
+func (c *Client) GetDownloadLink(ctx context.Context, path string) (string, *Response, error) {
+	u := fmt.Sprintf("%s/api/v2.1/dtable/app-download-link/?path=%s", c.Server, url.QueryEscape(path))
+	var out struct {
+		DownloadLink string `json:"download_link"`
+	}
+	resp, err := c.do(ctx, "GET", u, nil, &out)
+	if err != nil {
+		return "", resp, err
+	}
+	if out.DownloadLink == "" {
+		return "", resp, fmt.Errorf("download_link not found in response")
+	}
+	return out.DownloadLink, resp, nil
+}
+
+// Download copies the content behind a download link to w and returns the
+// number of bytes written. The Response carries no body.
+func (c *Client) Download(ctx context.Context, downloadURL string, w io.Writer) (int64, *Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", downloadURL, nil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("create download request: %w", err)
+	}
+
+	httpResp, err := c.transferClient(downloadRequestTimeout).Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("download request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	resp := &Response{StatusCode: httpResp.StatusCode, Header: httpResp.Header}
+	if err := CheckResponse(resp); err != nil {
+		return 0, resp, err
+	}
+
+	written, err := io.Copy(w, httpResp.Body)
+	if err != nil {
+		return written, resp, fmt.Errorf("save file: %w", err)
+	}
+	return written, resp, nil
+}
@@ -0,0 +1,53 @@
+package seatable
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUploadURL(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v2.1/dtable/app-upload-link/":
+			json.NewEncoder(w).Encode(UploadLink{
+				UploadLink:       "token",
+				ParentPath:       "/asset/base",
+				FileRelativePath: "files/2024-01",
+			})
+		case strings.HasPrefix(r.URL.Path, "/seafhttp/upload-api/"):
+			json.NewEncoder(w).Encode([]map[string]any{{"name": "a b.txt", "size": 3}})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	tests := []struct {
+		workspaceID int
+		want        string
+	}{
+		{0, ""},
+		{7, "/workspace/7/asset/base/files/2024-01/a%20b.txt"},
+	}
+	for _, tt := range tests {
+		c := newTestClient(t, handler)
+		c.WorkspaceID = tt.workspaceID
+
+		a, _, err := c.Upload(context.Background(), strings.NewReader("abc"), "a b.txt", "file")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := tt.want
+		if want != "" {
+			want = c.Server + want
+		}
+		if a.URL != want {
+			t.Errorf("workspace %d: URL = %q, want %q", tt.workspaceID, a.URL, want)
+		}
+		if a.Name != "a b.txt" || a.Size != 3 || a.Type != "file" {
+			t.Errorf("workspace %d: attachment = %+v", tt.workspaceID, a)
+		}
+	}
+}
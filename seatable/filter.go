@@ -0,0 +1,231 @@
+package seatable
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidFilter is returned for filter definitions that cannot be
+// compiled.
+var ErrInvalidFilter = errors.New("seatable: invalid filter")
+
+// Filter is a condition on one column or a group of filters joined by
+// Conjunction ("and" or "or"). A group has Filters; a condition has Column
+// and Operator.
+//
+// Operators: is, is_not, contains, does_not_contain, starts_with, ends_with,
+// greater, greater_or_equal, less, less_or_equal, is_empty, is_not_empty,
+// in, not_in, has_any_of, has_all_of, has_none_of, is_before, is_after,
+// is_on_or_before, is_on_or_after and is_within. in and the has_* operators
+// take a list; is_within takes {"from": ..., "to": ...}. The text operators
+// match their value literally, % and _ included.
+type Filter struct {
+	Conjunction string   `json:"conjunction,omitempty"`
+	Filters     []Filter `json:"filters,omitempty"`
+
+	Column   string `json:"column,omitempty"`
+	Operator string `json:"operator,omitempty"`
+	Value    any    `json:"value,omitempty"`
+}
+
+// ParseFilter reads a filter definition. A JSON array is read as an "and"
+// group of its elements.
+func ParseFilter(data []byte) (*Filter, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+	var f Filter
+	if data[0] == '[' {
+		f.Conjunction = "and"
+		if err := json.Unmarshal(data, &f.Filters); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+		}
+		return &f, nil
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+	}
+	return &f, nil
+}
+
+// Where compiles f into a SQL condition with ? placeholders for the columns
+// of t. Column names are resolved against t, so keys work as well. An empty
+// filter compiles to "".
+func (f *Filter) Where(t *Table) (string, []any, error) {
+	if f == nil {
+		return "", nil, nil
+	}
+	var params []any
+	clause, err := f.compile(t, &params)
+	return clause, params, err
+}
+
+func (f *Filter) compile(t *Table, params *[]any) (string, error) {
+	if f.Column == "" {
+		return f.compileGroup(t, params)
+	}
+
+	ident, err := t.QuoteColumn(f.Column)
+	if err != nil {
+		return "", err
+	}
+
+	bind := func(v any) string {
+		*params = append(*params, v)
+		return "?"
+	}
+	list := func() (string, error) {
+		vals, ok := f.Value.([]any)
+		if !ok {
+			vals = []any{f.Value}
+		}
+		if len(vals) == 0 {
+			return "", fmt.Errorf("%w: %s on %q needs at least one value", ErrInvalidFilter, f.Operator, f.Column)
+		}
+		marks := make([]string, len(vals))
+		for i, v := range vals {
+			marks[i] = bind(v)
+		}
+		return "(" + strings.Join(marks, ", ") + ")", nil
+	}
+	// like binds the value as a LIKE pattern. % and _ in the value match
+	// literally; backslash is the default escape character of SeaTable SQL.
+	like := func(not bool, prefix, suffix string) (string, error) {
+		if f.Value == nil {
+			return "", fmt.Errorf("%w: %s on %q needs a value", ErrInvalidFilter, f.Operator, f.Column)
+		}
+		op := " LIKE "
+		if not {
+			op = " NOT LIKE "
+		}
+		return ident + op + bind(prefix+likeEscaper.Replace(fmt.Sprint(f.Value))+suffix), nil
+	}
+
+	switch strings.ToLower(f.Operator) {
+	case "is", "equals", "=":
+		return ident + " = " + bind(f.Value), nil
+	case "is_not", "not_equals", "!=":
+		return ident + " <> " + bind(f.Value), nil
+	case "contains":
+		return like(false, "%", "%")
+	case "does_not_contain":
+		return like(true, "%", "%")
+	case "starts_with":
+		return like(false, "", "%")
+	case "ends_with":
+		return like(false, "%", "")
+	case "greater", "is_after", ">":
+		return ident + " > " + bind(f.Value), nil
+	case "greater_or_equal", "is_on_or_after", ">=":
+		return ident + " >= " + bind(f.Value), nil
+	case "less", "is_before", "<":
+		return ident + " < " + bind(f.Value), nil
+	case "less_or_equal", "is_on_or_before", "<=":
+		return ident + " <= " + bind(f.Value), nil
+	case "is_empty":
+		return ident + " IS NULL", nil
+	case "is_not_empty":
+		return ident + " IS NOT NULL", nil
+	case "in", "not_in":
+		vals, err := list()
+		if err != nil {
+			return "", err
+		}
+		if strings.EqualFold(f.Operator, "not_in") {
+			return ident + " NOT IN " + vals, nil
+		}
+		return ident + " IN " + vals, nil
+	case "has_any_of", "has_all_of", "has_none_of":
+		vals, err := list()
+		if err != nil {
+			return "", err
+		}
+		op := strings.ReplaceAll(strings.ToLower(f.Operator), "_", " ")
+		return ident + " " + op + " " + vals, nil
+	case "is_within", "between":
+		var r struct {
+			From any `json:"from"`
+			To   any `json:"to"`
+		}
+		b, _ := json.Marshal(f.Value)
+		if err := json.Unmarshal(b, &r); err != nil || r.From == nil || r.To == nil {
+			return "", fmt.Errorf("%w: %s on %q needs {\"from\", \"to\"}", ErrInvalidFilter, f.Operator, f.Column)
+		}
+		return "(" + ident + " >= " + bind(r.From) + " AND " + ident + " <= " + bind(r.To) + ")", nil
+	}
+	return "", fmt.Errorf("%w: unknown operator %q", ErrInvalidFilter, f.Operator)
+}
+
+// likeEscaper escapes the LIKE wildcards and the escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (f *Filter) compileGroup(t *Table, params *[]any) (string, error) {
+	join := " AND "
+	switch strings.ToLower(f.Conjunction) {
+	case "", "and":
+	case "or":
+		join = " OR "
+	default:
+		return "", fmt.Errorf("%w: unknown conjunction %q", ErrInvalidFilter, f.Conjunction)
+	}
+
+	parts := make([]string, 0, len(f.Filters))
+	for i := range f.Filters {
+		p, err := f.Filters[i].compile(t, params)
+		if err != nil {
+			return "", err
+		}
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	switch len(parts) {
+	case 0:
+		return "", nil
+	case 1:
+		return parts[0], nil
+	}
+	return "(" + strings.Join(parts, join) + ")", nil
+}
+
+// QueryRowsOptions narrows a QueryRows call.
+type QueryRowsOptions struct {
+	Start       int
+	Limit       int
+	ConvertKeys bool
+}
+
+// QueryRows returns one page of the rows of table matching filter. The
+// filter is compiled to a parameterized SQL query; rows are ordered by _id
+// so consecutive pages neither skip nor repeat rows.
+func (c *Client) QueryRows(ctx context.Context, table string, filter *Filter, opts *QueryRowsOptions) ([]Row, *Response, error) {
+	if opts == nil {
+		opts = &QueryRowsOptions{}
+	}
+	t, err := c.ResolveTable(ctx, table)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	limit := opts.Limit
+	if limit <= 0 || limit > maxSQLLimit {
+		limit = maxSQLLimit
+	}
+	query, params, err := NewSelect(t).WhereFilter(filter).OrderBy("_id", false).Limit(limit).Offset(opts.Start).Build()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res, resp, err := c.QuerySQL(ctx, query, &SQLOptions{Params: params, ConvertKeys: opts.ConvertKeys})
+	if err != nil {
+		return nil, resp, err
+	}
+	if err := res.Err(); err != nil {
+		return nil, resp, err
+	}
+	return res.Results, resp, nil
+}
@@ -0,0 +1,145 @@
+package seatable
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+var filterTable = &Table{Name: "Orders", Columns: []Column{
+	{Key: "0000", Name: "Name", Type: ColumnText},
+	{Key: "0001", Name: "Amount", Type: ColumnNumber},
+	{Key: "0002", Name: "Tags", Type: ColumnMultipleSelect},
+	{Key: "0003", Name: "Due", Type: ColumnDate},
+}}
+
+func TestFilterOperators(t *testing.T) {
+	tests := []struct {
+		filter Filter
+		want   string
+		params []any
+	}{
+		{Filter{Column: "Name", Operator: "is", Value: "a"}, "`Name` = ?", []any{"a"}},
+		{Filter{Column: "Name", Operator: "is_not", Value: "a"}, "`Name` <> ?", []any{"a"}},
+		{Filter{Column: "Name", Operator: "contains", Value: "a"}, "`Name` LIKE ?", []any{"%a%"}},
+		{Filter{Column: "Name", Operator: "does_not_contain", Value: "a"}, "`Name` NOT LIKE ?", []any{"%a%"}},
+		{Filter{Column: "Name", Operator: "starts_with", Value: "a"}, "`Name` LIKE ?", []any{"a%"}},
+		{Filter{Column: "Name", Operator: "ends_with", Value: "a"}, "`Name` LIKE ?", []any{"%a"}},
+		{Filter{Column: "Name", Operator: "contains", Value: `50%_a\b`}, "`Name` LIKE ?", []any{`%50\%\_a\\b%`}},
+		{Filter{Column: "Name", Operator: "starts_with", Value: 12.5}, "`Name` LIKE ?", []any{"12.5%"}},
+		{Filter{Column: "Amount", Operator: "greater", Value: 1.0}, "`Amount` > ?", []any{1.0}},
+		{Filter{Column: "Amount", Operator: "greater_or_equal", Value: 1.0}, "`Amount` >= ?", []any{1.0}},
+		{Filter{Column: "Amount", Operator: "less", Value: 1.0}, "`Amount` < ?", []any{1.0}},
+		{Filter{Column: "Amount", Operator: "less_or_equal", Value: 1.0}, "`Amount` <= ?", []any{1.0}},
+		{Filter{Column: "Name", Operator: "is_empty"}, "`Name` IS NULL", nil},
+		{Filter{Column: "Name", Operator: "is_not_empty"}, "`Name` IS NOT NULL", nil},
+		{Filter{Column: "Name", Operator: "in", Value: []any{"a", "b"}}, "`Name` IN (?, ?)", []any{"a", "b"}},
+		{Filter{Column: "Name", Operator: "not_in", Value: []any{"a"}}, "`Name` NOT IN (?)", []any{"a"}},
+		{Filter{Column: "Tags", Operator: "has_any_of", Value: []any{"x", "y"}}, "`Tags` has any of (?, ?)", []any{"x", "y"}},
+		{Filter{Column: "Tags", Operator: "has_all_of", Value: []any{"x"}}, "`Tags` has all of (?)", []any{"x"}},
+		{Filter{Column: "Tags", Operator: "has_none_of", Value: "x"}, "`Tags` has none of (?)", []any{"x"}},
+		{Filter{Column: "Due", Operator: "is_before", Value: "2024-01-01"}, "`Due` < ?", []any{"2024-01-01"}},
+		{Filter{Column: "Due", Operator: "is_after", Value: "2024-01-01"}, "`Due` > ?", []any{"2024-01-01"}},
+		{Filter{Column: "Due", Operator: "is_on_or_before", Value: "2024-01-01"}, "`Due` <= ?", []any{"2024-01-01"}},
+		{Filter{Column: "Due", Operator: "is_on_or_after", Value: "2024-01-01"}, "`Due` >= ?", []any{"2024-01-01"}},
+		{
+			Filter{Column: "Due", Operator: "is_within", Value: map[string]any{"from": "2024-01-01", "to": "2024-12-31"}},
+			"(`Due` >= ? AND `Due` <= ?)", []any{"2024-01-01", "2024-12-31"},
+		},
+		{Filter{Column: "0001", Operator: "=", Value: 2.0}, "`Amount` = ?", []any{2.0}},
+		{Filter{Column: "name", Operator: "IS", Value: "a"}, "`Name` = ?", []any{"a"}},
+		{
+			Filter{Conjunction: "or", Filters: []Filter{
+				{Column: "Name", Operator: "is", Value: "a"},
+				{Filters: []Filter{
+					{Column: "Amount", Operator: "greater", Value: 1.0},
+					{Column: "Amount", Operator: "less", Value: 9.0},
+				}},
+			}},
+			"(`Name` = ? OR (`Amount` > ? AND `Amount` < ?))", []any{"a", 1.0, 9.0},
+		},
+		{Filter{Conjunction: "and"}, "", nil},
+	}
+	for _, tt := range tests {
+		got, params, err := tt.filter.Where(filterTable)
+		if err != nil {
+			t.Errorf("%s %s: %v", tt.filter.Column, tt.filter.Operator, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s %s = %q, want %q", tt.filter.Column, tt.filter.Operator, got, tt.want)
+		}
+		if !reflect.DeepEqual(params, tt.params) {
+			t.Errorf("%s %s params = %v, want %v", tt.filter.Column, tt.filter.Operator, params, tt.params)
+		}
+	}
+}
+
+func TestFilterErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		target error
+	}{
+		{"unknown operator", Filter{Column: "Name", Operator: "like"}, ErrInvalidFilter},
+		{"contains without value", Filter{Column: "Name", Operator: "contains"}, ErrInvalidFilter},
+		{"ends_with without value", Filter{Column: "Name", Operator: "ends_with"}, ErrInvalidFilter},
+		{"empty list", Filter{Column: "Name", Operator: "in", Value: []any{}}, ErrInvalidFilter},
+		{"within without range", Filter{Column: "Due", Operator: "is_within", Value: "2024"}, ErrInvalidFilter},
+		{"unknown conjunction", Filter{Conjunction: "xor", Filters: []Filter{{Column: "Name", Operator: "is_empty"}}}, ErrInvalidFilter},
+	}
+	for _, tt := range tests {
+		if _, _, err := tt.filter.Where(filterTable); !errors.Is(err, tt.target) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.target)
+		}
+	}
+
+	_, _, err := (&Filter{Column: "Nmae", Operator: "is", Value: 1}).Where(filterTable)
+	var nameErr *NameError
+	if !errors.As(err, &nameErr) || len(nameErr.Suggestions) == 0 || nameErr.Suggestions[0] != "Name" {
+		t.Errorf("unknown column: err = %v, want a NameError suggesting Name", err)
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	f, err := ParseFilter([]byte(`[{"column": "Name", "operator": "is", "value": "a"}, {"column": "Amount", "operator": "greater", "value": 1}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, params, err := f.Where(filterTable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "(`Name` = ? AND `Amount` > ?)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if !reflect.DeepEqual(params, []any{"a", 1.0}) {
+		t.Errorf("params = %v", params)
+	}
+
+	if f, err := ParseFilter([]byte(" null ")); f != nil || err != nil {
+		t.Errorf("null: got %v, %v", f, err)
+	}
+	if _, err := ParseFilter([]byte("{")); !errors.Is(err, ErrInvalidFilter) {
+		t.Errorf("bad JSON: err = %v, want ErrInvalidFilter", err)
+	}
+}
+
+func TestQueryRowsMetadataError(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error_msg": "metadata unavailable"}`, http.StatusInternalServerError)
+	}))
+
+	rows, resp, err := c.QueryRows(context.Background(), "Orders", &Filter{Column: "Name", Operator: "is", Value: "a"}, nil)
+	if !IsStatus(err, http.StatusInternalServerError) {
+		t.Fatalf("err = %v, want a 500 APIError", err)
+	}
+	if resp == nil || resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("resp = %+v, want status 500", resp)
+	}
+	if rows != nil {
+		t.Errorf("rows = %v, want nil", rows)
+	}
+}
@@ -0,0 +1,42 @@
+package seatable
+
+import "context"
+
+// Link identifies a link column by its link id and the two tables it joins.
+type Link struct {
+	ID         string
+	Table      string
+	OtherTable string
+}
+
+// AddLink links rowID in l.Table to otherRowID in l.OtherTable.
+func (c *Client) AddLink(ctx context.Context, l Link, rowID, otherRowID string) (*Response, error) {
+	return c.do(ctx, "POST", c.endpoint(endpointLinks), l.pair(rowID, otherRowID), nil)
+}
+
+// RemoveLink removes the link between rowID and otherRowID.
+func (c *Client) RemoveLink(ctx context.Context, l Link, rowID, otherRowID string) (*Response, error) {
+	return c.do(ctx, "DELETE", c.endpoint(endpointLinks), l.pair(rowID, otherRowID), nil)
+}
+
+// UpdateLinks replaces the rows linked to rowID with otherRowIDs.
+func (c *Client) UpdateLinks(ctx context.Context, l Link, rowID string, otherRowIDs []string) (*Response, error) {
+	body := map[string]any{
+		"link_id":          l.ID,
+		"table_name":       l.Table,
+		"other_table_name": l.OtherTable,
+		"row_id":           rowID,
+		"other_rows_ids":   otherRowIDs,
+	}
+	return c.do(ctx, "PUT", c.endpoint(endpointLinks), body, nil)
+}
+
+func (l Link) pair(rowID, otherRowID string) map[string]any {
+	return map[string]any{
+		"link_id":            l.ID,
+		"table_name":         l.Table,
+		"other_table_name":   l.OtherTable,
+		"table_row_id":       rowID,
+		"other_table_row_id": otherRowID,
+	}
+}
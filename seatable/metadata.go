@@ -0,0 +1,79 @@
+package seatable
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// GetMetadata fetches the tables, columns and views of the base and refreshes
+// the schema cache with them.
+func (c *Client) GetMetadata(ctx context.Context) (*Metadata, *Response, error) {
+	// The gateway nests the schema under "metadata"; older servers may not.
+	var out struct {
+		Metadata *Metadata `json:"metadata"`
+		Tables   []Table   `json:"tables"`
+	}
+	resp, err := c.do(ctx, "GET", c.endpoint(endpointMetadata), nil, &out)
+	if err != nil {
+		return nil, resp, err
+	}
+	if out.Metadata == nil {
+		out.Metadata = &Metadata{Tables: out.Tables}
+	}
+	if out.Metadata.Tables == nil {
+		out.Metadata.Tables = []Table{}
+	}
+	c.storeSchema(out.Metadata, resp.Body)
+	return out.Metadata, resp, nil
+}
+
+// ListColumns returns the columns of a table, restricted to those visible in
+// view when it is not empty.
+func (c *Client) ListColumns(ctx context.Context, table, view string) ([]Column, *Response, error) {
+	u, err := url.Parse(c.endpoint(endpointColumns))
+	if err != nil {
+		return nil, nil, err
+	}
+	q := u.Query()
+	q.Set("table_name", table)
+	if strings.TrimSpace(view) != "" {
+		q.Set("view_name", view)
+	}
+	u.RawQuery = q.Encode()
+
+	var out struct {
+		Columns []Column `json:"columns"`
+	}
+	resp, err := c.do(ctx, "GET", u.String(), nil, &out)
+	if err != nil {
+		return nil, resp, err
+	}
+	if out.Columns == nil {
+		out.Columns = []Column{}
+	}
+	return out.Columns, resp, nil
+}
+
+// ListViews returns the views of a table.
+func (c *Client) ListViews(ctx context.Context, table string) ([]View, *Response, error) {
+	u, err := url.Parse(c.endpoint(endpointViews))
+	if err != nil {
+		return nil, nil, err
+	}
+	q := u.Query()
+	q.Set("table_name", table)
+	u.RawQuery = q.Encode()
+
+	var out struct {
+		Views []View `json:"views"`
+	}
+	resp, err := c.do(ctx, "GET", u.String(), nil, &out)
+	if err != nil {
+		return nil, resp, err
+	}
+	if out.Views == nil {
+		out.Views = []View{}
+	}
+	return out.Views, resp, nil
+}
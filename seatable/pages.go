@@ -0,0 +1,177 @@
+package seatable
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// MaxPageSize is the largest page ListRows returns.
+const MaxPageSize = 1000
+
+// FetchRowsOptions configures a FetchRows call.
+type FetchRowsOptions struct {
+	// View limits the rows to a view. It cannot be combined with Filter.
+	View   string
+	Filter *Filter
+	Start  int
+	// MaxRows caps the number of rows returned; 0 means no cap.
+	MaxRows     int
+	PageSize    int
+	ConvertKeys bool
+	// Concurrency is the number of pages fetched at the same time. Values
+	// below 2 fetch one page after another.
+	Concurrency int
+}
+
+// CountRows returns the number of rows of table matching filter.
+func (c *Client) CountRows(ctx context.Context, table string, filter *Filter) (int, *Response, error) {
+	t, err := c.ResolveTable(ctx, table)
+	if err != nil {
+		return 0, errorResponse(err), err
+	}
+	query, params, err := NewSelect(t).Count().WhereFilter(filter).Build()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	res, resp, err := c.QuerySQL(ctx, query, &SQLOptions{Params: params})
+	if err != nil {
+		return 0, resp, err
+	}
+	if err := res.Err(); err != nil {
+		return 0, resp, err
+	}
+	if len(res.Results) == 0 {
+		return 0, resp, fmt.Errorf("seatable: count query returned no rows")
+	}
+	for _, v := range res.Results[0] {
+		switch n := v.(type) {
+		case float64:
+			return int(n), resp, nil
+		case string:
+			if i, err := strconv.Atoi(n); err == nil {
+				return i, resp, nil
+			}
+		}
+	}
+	return 0, resp, fmt.Errorf("seatable: count query returned no number")
+}
+
+// FetchRows reads rows page by page, from a view through ListRows or with a
+// filter through QueryRows. With Concurrency above one, the total is counted
+// first and pages are fetched by a bounded pool of workers; requests still
+// go through the client's rate limiter. Pages are returned in order.
+//
+// When a page fails, the rows before it are returned together with the
+// failing response and its error. An *APIError always comes with a Response.
+func (c *Client) FetchRows(ctx context.Context, table string, opts *FetchRowsOptions) ([]Row, *Response, error) {
+	if opts == nil {
+		opts = &FetchRowsOptions{}
+	}
+	pageSize := opts.PageSize
+	if pageSize <= 0 || pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+	maxRows := opts.MaxRows
+	if maxRows <= 0 {
+		maxRows = int(^uint(0) >> 1)
+	}
+
+	// Views cannot be counted with SQL; they are read one wave of pages at
+	// a time until a short page shows the end.
+	var last *Response
+	counted := opts.Concurrency > 1 && opts.View == ""
+	if counted {
+		total, resp, err := c.CountRows(ctx, table, opts.Filter)
+		if err != nil {
+			return []Row{}, resp, err
+		}
+		last = resp
+		maxRows = min(maxRows, max(total-opts.Start, 0))
+	}
+
+	workers := max(opts.Concurrency, 1)
+	rows := []Row{}
+	offset := opts.Start
+	end := opts.Start + maxRows
+	if end < opts.Start {
+		end = maxRows
+	}
+	for offset < end {
+		var pages []page
+		for offset < end && (counted || len(pages) < workers) {
+			limit := min(pageSize, end-offset)
+			pages = append(pages, page{offset: offset, limit: limit})
+			offset += limit
+		}
+
+		c.fetchPages(ctx, table, opts, pages, workers)
+		for _, p := range pages {
+			if p.resp != nil {
+				last = p.resp
+			}
+			if p.err != nil {
+				if p.resp == nil {
+					last = errorResponse(p.err)
+				}
+				return rows, last, p.err
+			}
+			rows = append(rows, p.rows...)
+			if len(p.rows) < p.limit {
+				return rows, last, nil
+			}
+		}
+	}
+	if last == nil {
+		last = &Response{StatusCode: 200}
+	}
+	return rows, last, nil
+}
+
+// page is one ListRows or QueryRows call of FetchRows.
+type page struct {
+	offset, limit int
+
+	rows []Row
+	resp *Response
+	err  error
+}
+
+// fetchPages fills in pages using up to workers concurrent requests.
+func (c *Client) fetchPages(ctx context.Context, table string, opts *FetchRowsOptions, pages []page, workers int) {
+	var wg sync.WaitGroup
+	jobs := make(chan *page)
+	for w := 0; w < min(workers, len(pages)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range jobs {
+				if err := ctx.Err(); err != nil {
+					p.err = err
+					continue
+				}
+				if opts.Filter != nil {
+					p.rows, p.resp, p.err = c.QueryRows(ctx, table, opts.Filter, &QueryRowsOptions{
+						Start:       p.offset,
+						Limit:       p.limit,
+						ConvertKeys: opts.ConvertKeys,
+					})
+				} else {
+					p.rows, p.resp, p.err = c.ListRows(ctx, table, &ListRowsOptions{
+						View:        opts.View,
+						Start:       p.offset,
+						Limit:       p.limit,
+						ConvertKeys: opts.ConvertKeys,
+					})
+				}
+			}
+		}()
+	}
+	for i := range pages {
+		jobs <- &pages[i]
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,112 @@
+package seatable
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var rowsPagePattern = regexp.MustCompile(`LIMIT (\d+)(?: OFFSET (\d+))?$`)
+
+// ordersServer serves the metadata of an Orders table with total rows, and
+// SQL pages and counts over it.
+func ordersServer(total int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/metadata/") {
+			json.NewEncoder(w).Encode(map[string]any{"metadata": Metadata{Tables: []Table{*filterTable}}})
+			return
+		}
+		var body struct {
+			SQL string `json:"sql"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if strings.Contains(body.SQL, "COUNT(*)") {
+			json.NewEncoder(w).Encode(SQLResult{Success: true, Results: []Row{{"COUNT(*)": total}}})
+			return
+		}
+		rows := []Row{}
+		if m := rowsPagePattern.FindStringSubmatch(body.SQL); m != nil {
+			limit, _ := strconv.Atoi(m[1])
+			offset, _ := strconv.Atoi(m[2])
+			for i := offset; i < min(offset+limit, total); i++ {
+				rows = append(rows, Row{"_id": fmt.Sprintf("r%d", i)})
+			}
+		}
+		json.NewEncoder(w).Encode(SQLResult{Success: true, Results: rows})
+	})
+}
+
+func TestFetchRowsConcurrent(t *testing.T) {
+	c := newTestClient(t, ordersServer(2500))
+	rows, resp, err := c.FetchRows(context.Background(), "Orders", &FetchRowsOptions{
+		Filter:      &Filter{Column: "Name", Operator: "is_not_empty"},
+		Start:       100,
+		Concurrency: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("resp = %+v, want status 200", resp)
+	}
+	if len(rows) != 2400 {
+		t.Fatalf("got %d rows, want 2400", len(rows))
+	}
+	for i, row := range rows {
+		if want := fmt.Sprintf("r%d", i+100); row.ID() != want {
+			t.Fatalf("row %d = %s, want %s", i, row.ID(), want)
+		}
+	}
+}
+
+func TestFetchRowsMetadataError(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error_msg": "metadata unavailable"}`, http.StatusInternalServerError)
+	}))
+
+	for _, concurrency := range []int{1, 4} {
+		rows, resp, err := c.FetchRows(context.Background(), "Orders", &FetchRowsOptions{
+			Filter:      &Filter{Column: "Name", Operator: "is_not_empty"},
+			Concurrency: concurrency,
+		})
+		if !IsStatus(err, http.StatusInternalServerError) {
+			t.Fatalf("concurrency %d: err = %v, want a 500 APIError", concurrency, err)
+		}
+		if resp == nil || resp.StatusCode != http.StatusInternalServerError {
+			t.Errorf("concurrency %d: resp = %+v, want status 500", concurrency, resp)
+		}
+		if len(rows) != 0 {
+			t.Errorf("concurrency %d: got %d rows, want none", concurrency, len(rows))
+		}
+	}
+}
+
+func TestFetchRowsQueryFailure(t *testing.T) {
+	orders := ordersServer(2500)
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/metadata/") {
+			orders.ServeHTTP(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"success": false, "error_message": "column type mismatch"})
+	}))
+
+	for _, concurrency := range []int{1, 4} {
+		_, resp, err := c.FetchRows(context.Background(), "Orders", &FetchRowsOptions{
+			Filter:      &Filter{Column: "Amount", Operator: "greater", Value: "x"},
+			Concurrency: concurrency,
+		})
+		if !errors.Is(err, ErrSQL) || !strings.Contains(err.Error(), "column type mismatch") {
+			t.Errorf("concurrency %d: err = %v, want ErrSQL with the server message", concurrency, err)
+		}
+		if resp == nil || resp.StatusCode != http.StatusOK {
+			t.Errorf("concurrency %d: resp = %+v, want status 200", concurrency, resp)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package seatable
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrSQLParams is returned when the parameters do not fit the placeholders
+// of a statement.
+var ErrSQLParams = errors.New("seatable: invalid SQL parameters")
+
+// BindParams rewrites query for the SQL API, which only knows positional ?
+// placeholders. :name placeholders are bound from named and may be used more
+// than once. A list bound to a placeholder, named or positional, expands to
+// one ? per element, so "IN (:ids)" works with any number of ids; an empty
+// list expands to NULL. Placeholders inside string literals, quoted
+// identifiers and comments are left alone.
+func BindParams(query string, positional []any, named map[string]any) (string, []any, error) {
+	if len(named) > 0 && len(positional) > 0 {
+		return "", nil, fmt.Errorf("%w: use either positional or named parameters", ErrSQLParams)
+	}
+
+	var (
+		b    strings.Builder
+		args []any
+		next int
+	)
+	bind := func(v any) {
+		list, ok := expandList(v)
+		if !ok {
+			b.WriteByte('?')
+			args = append(args, v)
+			return
+		}
+		if len(list) == 0 {
+			b.WriteString("NULL")
+			return
+		}
+		for i, item := range list {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteByte('?')
+			args = append(args, item)
+		}
+	}
+
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case ch == '\'' || ch == '"' || ch == '`':
+			end := skipQuoted(query, i)
+			b.WriteString(query[i:end])
+			i = end - 1
+
+		case ch == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+			b.WriteString(query[i : i+end])
+			i += end - 1
+
+		case ch == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				end = len(query) - i
+			} else {
+				end += 4
+			}
+			b.WriteString(query[i : i+end])
+			i += end - 1
+
+		case ch == '?' && named == nil:
+			if next >= len(positional) {
+				return "", nil, fmt.Errorf("%w: %d parameters for more placeholders", ErrSQLParams, len(positional))
+			}
+			bind(positional[next])
+			next++
+
+		case ch == ':' && named != nil && i+1 < len(query) && isNameStart(query[i+1]) && (i == 0 || query[i-1] != ':'):
+			end := i + 1
+			for end < len(query) && isNameChar(query[end]) {
+				end++
+			}
+			name := query[i+1 : end]
+			v, ok := named[name]
+			if !ok {
+				return "", nil, fmt.Errorf("%w: no value for :%s", ErrSQLParams, name)
+			}
+			bind(v)
+			i = end - 1
+
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	if named == nil && next < len(positional) {
+		return "", nil, fmt.Errorf("%w: %d parameters for %d placeholders", ErrSQLParams, len(positional), next)
+	}
+	return b.String(), args, nil
+}
+
+// skipQuoted returns the index just past the quoted section starting at i.
+// Doubled quotes and backslash escapes stay inside the section.
+func skipQuoted(s string, i int) int {
+	q := s[i]
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			if q != '`' {
+				j++
+			}
+		case q:
+			if j+1 < len(s) && s[j+1] == q {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+// expandList returns the elements of v when it is a slice or array other
+// than []byte.
+func expandList(v any) ([]any, bool) {
+	if v == nil {
+		return nil, false
+	}
+	if list, ok := v.([]any); ok {
+		return list, true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	if rv.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+	list := make([]any, rv.Len())
+	for i := range list {
+		list[i] = rv.Index(i).Interface()
+	}
+	return list, true
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,128 @@
+package seatable
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBindParams(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		positional []any
+		named      map[string]any
+		want       string
+		args       []any
+	}{
+		{
+			name:       "positional",
+			query:      "SELECT * FROM t WHERE a = ? AND b = ?",
+			positional: []any{1, "x"},
+			want:       "SELECT * FROM t WHERE a = ? AND b = ?",
+			args:       []any{1, "x"},
+		},
+		{
+			name:       "positional list",
+			query:      "SELECT * FROM t WHERE id IN (?)",
+			positional: []any{[]string{"a", "b", "c"}},
+			want:       "SELECT * FROM t WHERE id IN (?, ?, ?)",
+			args:       []any{"a", "b", "c"},
+		},
+		{
+			name:  "named reused",
+			query: "SELECT * FROM t WHERE a = :v OR b = :v",
+			named: map[string]any{"v": 2},
+			want:  "SELECT * FROM t WHERE a = ? OR b = ?",
+			args:  []any{2, 2},
+		},
+		{
+			name:  "named list",
+			query: "SELECT * FROM t WHERE id IN (:ids)",
+			named: map[string]any{"ids": []any{"a", "b"}},
+			want:  "SELECT * FROM t WHERE id IN (?, ?)",
+			args:  []any{"a", "b"},
+		},
+		{
+			name:  "empty list",
+			query: "SELECT * FROM t WHERE id IN (:ids)",
+			named: map[string]any{"ids": []any{}},
+			want:  "SELECT * FROM t WHERE id IN (NULL)",
+		},
+		{
+			name:  "bytes are not a list",
+			query: "SELECT * FROM t WHERE b = :b",
+			named: map[string]any{"b": []byte("ab")},
+			want:  "SELECT * FROM t WHERE b = ?",
+			args:  []any{[]byte("ab")},
+		},
+		{
+			name:  "quoted placeholders",
+			query: "SELECT * FROM t WHERE a = ':x' AND `c:x?` = \"?\" AND b = :x",
+			named: map[string]any{"x": 1},
+			want:  "SELECT * FROM t WHERE a = ':x' AND `c:x?` = \"?\" AND b = ?",
+			args:  []any{1},
+		},
+		{
+			name:       "escaped quotes",
+			query:      `SELECT * FROM t WHERE a = 'it''s ?' AND b = 'a\'?' AND c = ?`,
+			positional: []any{1},
+			want:       `SELECT * FROM t WHERE a = 'it''s ?' AND b = 'a\'?' AND c = ?`,
+			args:       []any{1},
+		},
+		{
+			name:  "comments",
+			query: "SELECT * FROM t -- :x ?\nWHERE /* :x ? */ a = :x",
+			named: map[string]any{"x": 1},
+			want:  "SELECT * FROM t -- :x ?\nWHERE /* :x ? */ a = ?",
+			args:  []any{1},
+		},
+		{
+			name:  "casts",
+			query: "SELECT a::text FROM t WHERE b = :b",
+			named: map[string]any{"b": 1},
+			want:  "SELECT a::text FROM t WHERE b = ?",
+			args:  []any{1},
+		},
+		{
+			name:  "question mark with named",
+			query: "SELECT * FROM t WHERE a = :a AND b = ?",
+			named: map[string]any{"a": 1},
+			want:  "SELECT * FROM t WHERE a = ? AND b = ?",
+			args:  []any{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args, err := BindParams(tt.query, tt.positional, tt.named)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("query = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("args = %v, want %v", args, tt.args)
+			}
+		})
+	}
+}
+
+func TestBindParamsErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		positional []any
+		named      map[string]any
+	}{
+		{"both kinds", "SELECT ? , :a", []any{1}, map[string]any{"a": 1}},
+		{"too few positional", "SELECT * FROM t WHERE a = ? AND b = ?", []any{1}, nil},
+		{"too many positional", "SELECT * FROM t WHERE a = ?", []any{1, 2}, nil},
+		{"missing name", "SELECT * FROM t WHERE a = :a", nil, map[string]any{"b": 1}},
+	}
+	for _, tt := range tests {
+		if _, _, err := BindParams(tt.query, tt.positional, tt.named); !errors.Is(err, ErrSQLParams) {
+			t.Errorf("%s: err = %v, want ErrSQLParams", tt.name, err)
+		}
+	}
+}
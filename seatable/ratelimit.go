@@ -0,0 +1,79 @@
+package seatable
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// tokenBucket is a continuously refilled token-bucket rate limiter.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+var (
+	baseLimiters   = make(map[string]*tokenBucket)
+	baseLimitersMu sync.Mutex
+)
+
+// baseRateLimiter returns the limiter shared by every client of a base and
+// sets its budget to perMinute requests per minute. The most recent call wins
+// when several clients configure different budgets for the same base.
+func baseRateLimiter(baseUUID string, perMinute int) *tokenBucket {
+	baseLimitersMu.Lock()
+	defer baseLimitersMu.Unlock()
+	b, ok := baseLimiters[baseUUID]
+	if !ok {
+		b = &tokenBucket{last: time.Now()}
+		baseLimiters[baseUUID] = b
+	}
+	b.setRate(perMinute)
+	if !ok {
+		b.tokens = b.burst
+	}
+	return b
+}
+
+func (b *tokenBucket) setRate(perMinute int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refillLocked(time.Now())
+	b.rate = float64(perMinute) / 60
+	// Allow about one second worth of requests to go out back to back.
+	b.burst = math.Max(1, b.rate)
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+func (b *tokenBucket) refillLocked(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rate)
+	}
+	b.last = now
+}
+
+// wait blocks until a request may be sent or ctx is done.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.refillLocked(now)
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		d := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		if err := sleepContext(ctx, d); err != nil {
+			return err
+		}
+	}
+}
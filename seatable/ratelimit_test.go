@@ -0,0 +1,79 @@
+package seatable
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// forgetLimiters drops the shared limiters of bases once the test is done,
+// so repeated runs start with a full budget.
+func forgetLimiters(t *testing.T, bases ...string) {
+	t.Cleanup(func() {
+		baseLimitersMu.Lock()
+		defer baseLimitersMu.Unlock()
+		for _, base := range bases {
+			delete(baseLimiters, base)
+		}
+	})
+}
+
+func TestBaseRateLimiterShared(t *testing.T) {
+	forgetLimiters(t, "limit-shared", "limit-other")
+	a := NewClient("https://example.com", nil)
+	a.BaseUUID = "limit-shared"
+	b := NewClient("https://example.com", nil)
+	b.BaseUUID = "limit-shared"
+	other := NewClient("https://example.com", nil)
+	other.BaseUUID = "limit-other"
+
+	a.SetRateLimit(60)
+	b.SetRateLimit(60)
+	other.SetRateLimit(60)
+	if a.limiter == nil || a.limiter != b.limiter {
+		t.Fatal("clients of the same base do not share a limiter")
+	}
+	if a.limiter == other.limiter {
+		t.Fatal("clients of different bases share a limiter")
+	}
+
+	// One request per second with a burst of one: a's request uses up the
+	// budget b would need.
+	if err := a.limiter.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := b.limiter.wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("second client waited with err = %v, want context.DeadlineExceeded", err)
+	}
+	if err := other.limiter.wait(ctx); err != nil {
+		t.Errorf("other base: %v", err)
+	}
+
+	b.SetRateLimit(0)
+	if b.limiter != nil {
+		t.Error("SetRateLimit(0) kept the limiter")
+	}
+}
+
+func TestTokenBucketRate(t *testing.T) {
+	forgetLimiters(t, "limit-rate")
+	b := baseRateLimiter("limit-rate", 1200)
+	start := time.Now()
+	for i := 0; i < 24; i++ {
+		if err := b.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A burst of 20 goes out at once; the next 4 are spaced 50ms apart.
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond || elapsed > time.Second {
+		t.Errorf("24 requests at 20/s took %v, want about 200ms", elapsed)
+	}
+
+	// Lowering the budget caps the tokens already saved up.
+	baseRateLimiter("limit-rate", 60)
+	if b.burst != 1 || b.tokens > 1 {
+		t.Errorf("after lowering the rate: burst %v, tokens %v", b.burst, b.tokens)
+	}
+}
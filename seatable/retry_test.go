@@ -0,0 +1,147 @@
+package seatable
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond}
+	for retry, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond} {
+		if got := p.delay(retry+1, nil); got != want {
+			t.Errorf("delay(%d) = %v, want %v", retry+1, got, want)
+		}
+	}
+	if got := p.delay(20, nil); got != maxRetryDelay {
+		t.Errorf("delay(20) = %v, want %v", got, maxRetryDelay)
+	}
+	if got := (RetryPolicy{}).delay(1, nil); got != defaultRetryBaseDelay {
+		t.Errorf("zero policy delay(1) = %v, want %v", got, defaultRetryBaseDelay)
+	}
+
+	p.Jitter = true
+	for i := 0; i < 100; i++ {
+		if got := p.delay(2, nil); got < 100*time.Millisecond || got > 200*time.Millisecond {
+			t.Fatalf("jittered delay(2) = %v, want between 100ms and 200ms", got)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"2", 2 * time.Second, true},
+		{" 0 ", 0, true},
+		{"-5", 0, true},
+		{"3600", maxRetryAfter, true},
+		{time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 0, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		header := http.Header{}
+		if tt.value != "" {
+			header.Set("Retry-After", tt.value)
+		}
+		got, ok := parseRetryAfter(header)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	future := time.Now().Add(90 * time.Second).UTC().Format(http.TimeFormat)
+	got, ok := parseRetryAfter(http.Header{"Retry-After": {future}})
+	if !ok || got < 80*time.Second || got > 90*time.Second {
+		t.Errorf("parseRetryAfter(%q) = %v, %v, want about 90s", future, got, ok)
+	}
+
+	p := RetryPolicy{BaseDelay: time.Second}
+	if got := p.delay(3, http.Header{"Retry-After": {"1"}}); got != time.Second {
+		t.Errorf("delay with Retry-After = %v, want 1s", got)
+	}
+}
+
+// statusServer answers with the given statuses in turn and 200 afterwards.
+type statusServer struct {
+	statuses []int
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (s *statusServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.calls <= len(s.statuses) {
+		if s.statuses[s.calls-1] == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "0")
+		}
+		w.WriteHeader(s.statuses[s.calls-1])
+		w.Write([]byte(`{"error_msg":"try again"}`))
+		return
+	}
+	w.Write([]byte(`{}`))
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		retryPost bool
+		statuses  []int
+		calls     int
+		status    int
+	}{
+		{"gateway errors are retried", "GET", false, []int{503, 502}, 3, 200},
+		{"rate limit is retried", "GET", false, []int{429}, 2, 200},
+		{"attempts run out", "GET", false, []int{504, 504, 504, 504}, 3, 504},
+		{"server error is final", "GET", false, []int{500}, 1, 500},
+		{"client error is final", "PUT", false, []int{400}, 1, 400},
+		{"POST is not retried", "POST", false, []int{503}, 1, 503},
+		{"POST is retried when allowed", "POST", true, []int{503}, 2, 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &statusServer{statuses: tt.statuses}
+			c := newTestClient(t, srv)
+			c.Retry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, RetryPost: tt.retryPost}
+
+			resp, err := c.do(context.Background(), tt.method, c.Server+"/api/v2.1/test/", map[string]any{}, nil)
+			if tt.status == 200 && err != nil {
+				t.Fatal(err)
+			}
+			if tt.status != 200 && !IsStatus(err, tt.status) {
+				t.Errorf("err = %v, want %d", err, tt.status)
+			}
+			if resp == nil || resp.StatusCode != tt.status {
+				t.Errorf("resp = %+v, want status %d", resp, tt.status)
+			}
+			if srv.calls != tt.calls {
+				t.Errorf("made %d calls, want %d", srv.calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestRetryCanceled(t *testing.T) {
+	srv := &statusServer{statuses: []int{503, 503, 503}}
+	c := newTestClient(t, srv)
+	c.Retry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	resp, err := c.do(ctx, "GET", c.Server+"/api/v2.1/test/", nil, nil)
+	if err != context.DeadlineExceeded {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if resp == nil || resp.StatusCode != 503 || srv.calls != 1 {
+		t.Errorf("resp = %+v after %d calls, want the first 503", resp, srv.calls)
+	}
+}
@@ -0,0 +1,195 @@
+package seatable
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ListRowsOptions narrows a ListRows call.
+type ListRowsOptions struct {
+	View        string
+	Start       int
+	Limit       int
+	ConvertKeys bool
+}
+
+// ListRows returns one page of rows of a table.
+func (c *Client) ListRows(ctx context.Context, table string, opts *ListRowsOptions) ([]Row, *Response, error) {
+	if opts == nil {
+		opts = &ListRowsOptions{}
+	}
+	u, err := url.Parse(c.endpoint(endpointRows))
+	if err != nil {
+		return nil, nil, err
+	}
+	q := u.Query()
+	q.Set("table_name", table)
+	if strings.TrimSpace(opts.View) != "" {
+		q.Set("view_name", opts.View)
+	}
+	if opts.Start > 0 {
+		q.Set("start", strconv.Itoa(opts.Start))
+	}
+	if opts.Limit > 0 {
+		q.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.ConvertKeys {
+		q.Set("convert_keys", "true")
+	}
+	u.RawQuery = q.Encode()
+
+	var out struct {
+		Rows []Row `json:"rows"`
+	}
+	resp, err := c.do(ctx, "GET", u.String(), nil, &out)
+	if err != nil {
+		return nil, resp, err
+	}
+	if out.Rows == nil {
+		out.Rows = []Row{}
+	}
+	return out.Rows, resp, nil
+}
+
+// GetRowOptions narrows a GetRow call.
+type GetRowOptions struct {
+	View        string
+	ConvertKeys bool
+}
+
+// GetRow returns a single row by id.
+func (c *Client) GetRow(ctx context.Context, table, rowID string, opts *GetRowOptions) (Row, *Response, error) {
+	if opts == nil {
+		opts = &GetRowOptions{}
+	}
+	u, err := url.Parse(c.endpoint(endpointRow, rowID))
+	if err != nil {
+		return nil, nil, err
+	}
+	q := u.Query()
+	q.Set("table_name", table)
+	if strings.TrimSpace(opts.View) != "" {
+		q.Set("view_name", opts.View)
+	}
+	if opts.ConvertKeys {
+		q.Set("convert_keys", "true")
+	}
+	u.RawQuery = q.Encode()
+
+	var out Row
+	resp, err := c.do(ctx, "GET", u.String(), nil, &out)
+	if err != nil {
+		return nil, resp, err
+	}
+	// Some servers wrap the row in {"row": {...}}.
+	if inner, ok := out["row"].(map[string]any); ok {
+		out = Row(inner)
+	}
+	return out, resp, nil
+}
+
+// AppendRow adds a row to the end of a table.
+func (c *Client) AppendRow(ctx context.Context, table string, row Row) (*Response, error) {
+	body := map[string]any{
+		"table_name": table,
+		"row":        row,
+	}
+	return c.do(ctx, "POST", c.endpoint(endpointRows), body, nil)
+}
+
+// Positions for InsertRow relative to the anchor row.
+const (
+	InsertAbove = "insert_above"
+	InsertBelow = "insert_below"
+)
+
+// InsertRow adds a row directly above or below the anchor row and returns
+// the id of the new row when the server reports it.
+func (c *Client) InsertRow(ctx context.Context, table string, row Row, anchorRowID, position string) (string, *Response, error) {
+	if position != InsertAbove {
+		position = InsertBelow
+	}
+	body := map[string]any{
+		"table_name":          table,
+		"row":                 row,
+		"anchor_row_id":       anchorRowID,
+		"row_insert_position": position,
+	}
+	var out struct {
+		ID     string `json:"_id"`
+		RowIDs []Row  `json:"row_ids"`
+	}
+	resp, err := c.do(ctx, "POST", c.endpoint(endpointRows), body, &out)
+	if err != nil {
+		return "", resp, err
+	}
+	if out.ID == "" && len(out.RowIDs) > 0 {
+		out.ID = out.RowIDs[0].ID()
+	}
+	return out.ID, resp, nil
+}
+
+// AppendRowsResult is returned by AppendRows.
+type AppendRowsResult struct {
+	InsertedRowCount int   `json:"inserted_row_count"`
+	RowIDs           []Row `json:"row_ids"`
+}
+
+// AppendRows adds several rows to the end of a table in one request.
+func (c *Client) AppendRows(ctx context.Context, table string, rows []Row) (*AppendRowsResult, *Response, error) {
+	body := map[string]any{
+		"table_name": table,
+		"rows":       rows,
+	}
+	var out AppendRowsResult
+	resp, err := c.do(ctx, "POST", c.endpoint(endpointBatchAppendRows), body, &out)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &out, resp, nil
+}
+
+// UpdateRow changes the given fields of a row.
+func (c *Client) UpdateRow(ctx context.Context, table, rowID string, row Row) (*Response, error) {
+	body := map[string]any{
+		"table_name": table,
+		"row_id":     rowID,
+		"row":        row,
+	}
+	return c.do(ctx, "PUT", c.endpoint(endpointRows), body, nil)
+}
+
+// DeleteRow removes a row.
+func (c *Client) DeleteRow(ctx context.Context, table, rowID string) (*Response, error) {
+	body := map[string]any{
+		"table_name": table,
+		"row_id":     rowID,
+	}
+	return c.do(ctx, "DELETE", c.endpoint(endpointRows), body, nil)
+}
+
+// RowUpdate is one entry of an UpdateRows call.
+type RowUpdate struct {
+	RowID string `json:"row_id"`
+	Row   Row    `json:"row"`
+}
+
+// UpdateRows changes several rows in one request.
+func (c *Client) UpdateRows(ctx context.Context, table string, updates []RowUpdate) (*Response, error) {
+	body := map[string]any{
+		"table_name": table,
+		"updates":    updates,
+	}
+	return c.do(ctx, "PUT", c.endpoint(endpointBatchUpdateRows), body, nil)
+}
+
+// DeleteRows removes several rows in one request.
+func (c *Client) DeleteRows(ctx context.Context, table string, rowIDs []string) (*Response, error) {
+	body := map[string]any{
+		"table_name": table,
+		"row_ids":    rowIDs,
+	}
+	return c.do(ctx, "DELETE", c.endpoint(endpointBatchDeleteRows), body, nil)
+}
@@ -0,0 +1,283 @@
+package seatable
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSchemaTTL is how long cached metadata is used before it is fetched
+// again.
+const DefaultSchemaTTL = 5 * time.Minute
+
+// schemaCache holds the last metadata fetched for a client.
+type schemaCache struct {
+	mu      sync.Mutex
+	md      *Metadata
+	body    []byte
+	fetched time.Time
+
+	// users maps user ids to contact emails; see UserEmails.
+	users        map[string]string
+	usersFetched time.Time
+}
+
+// NameError reports a table or column name that does not exist. Suggestions
+// lists close matches.
+type NameError struct {
+	Kind        string // "table" or "column"
+	Name        string
+	Table       string // for columns, the table searched
+	Suggestions []string
+}
+
+func (e *NameError) Error() string {
+	msg := fmt.Sprintf("seatable: unknown %s %q", e.Kind, e.Name)
+	if e.Table != "" {
+		msg += fmt.Sprintf(" in table %q", e.Table)
+	}
+	if len(e.Suggestions) > 0 {
+		msg += fmt.Sprintf(" (did you mean %q?)", e.Suggestions[0])
+	}
+	return msg
+}
+
+// Schema returns the base metadata, served from cache while it is younger
+// than the client's SchemaTTL. The returned value must not be modified.
+func (c *Client) Schema(ctx context.Context) (*Metadata, error) {
+	md, _, err := c.CachedMetadata(ctx)
+	return md, err
+}
+
+// CachedMetadata is GetMetadata served from the schema cache. A cached answer
+// comes with a synthesized 200 Response carrying the original body.
+func (c *Client) CachedMetadata(ctx context.Context) (*Metadata, *Response, error) {
+	ttl := c.SchemaTTL
+	if ttl <= 0 {
+		ttl = DefaultSchemaTTL
+	}
+	c.schema.mu.Lock()
+	md, body, fetched := c.schema.md, c.schema.body, c.schema.fetched
+	c.schema.mu.Unlock()
+	if md != nil && time.Since(fetched) < ttl {
+		return md, &Response{StatusCode: http.StatusOK, Body: body}, nil
+	}
+	return c.GetMetadata(ctx)
+}
+
+// storeSchema replaces the cached metadata.
+func (c *Client) storeSchema(md *Metadata, body []byte) {
+	c.schema.mu.Lock()
+	defer c.schema.mu.Unlock()
+	c.schema.md, c.schema.body, c.schema.fetched = md, body, time.Now()
+}
+
+// InvalidateSchema drops the cached metadata and users so the next lookup
+// fetches them again. Call it after changing tables, columns or views.
+func (c *Client) InvalidateSchema() {
+	c.schema.mu.Lock()
+	defer c.schema.mu.Unlock()
+	c.schema.md, c.schema.body = nil, nil
+	c.schema.users = nil
+}
+
+// lookupSchema returns the cached metadata, refreshing it once when found
+// reports a miss, in case the schema changed since it was cached.
+func (c *Client) lookupSchema(ctx context.Context, found func(*Metadata) bool) (*Metadata, error) {
+	md, err := c.Schema(ctx)
+	if err != nil || found(md) {
+		return md, err
+	}
+	c.InvalidateSchema()
+	return c.Schema(ctx)
+}
+
+// ResolveTable finds a table by name or id. Names are matched exactly first,
+// then case-insensitively. Unknown names fail with a *NameError.
+func (c *Client) ResolveTable(ctx context.Context, name string) (*Table, error) {
+	md, err := c.lookupSchema(ctx, func(md *Metadata) bool { return findTable(md, name) != nil })
+	if err != nil {
+		return nil, err
+	}
+	if t := findTable(md, name); t != nil {
+		return t, nil
+	}
+	return nil, unknownTable(md, name)
+}
+
+// ResolveColumn finds a column of table by name or key.
+func (c *Client) ResolveColumn(ctx context.Context, table, name string) (*Column, error) {
+	t, err := c.resolveColumns(ctx, table, []string{name})
+	if err != nil {
+		return nil, err
+	}
+	return t.Column(name), nil
+}
+
+// ValidateColumns checks that every name is a column of table and reports
+// the first unknown one. System fields starting with "_" are skipped.
+func (c *Client) ValidateColumns(ctx context.Context, table string, names []string) error {
+	_, err := c.resolveColumns(ctx, table, names)
+	return err
+}
+
+func (c *Client) resolveColumns(ctx context.Context, table string, names []string) (*Table, error) {
+	missing := func(t *Table) string {
+		for _, name := range names {
+			if !strings.HasPrefix(name, "_") && t.Column(name) == nil {
+				return name
+			}
+		}
+		return ""
+	}
+	md, err := c.lookupSchema(ctx, func(md *Metadata) bool {
+		t := findTable(md, table)
+		return t != nil && missing(t) == ""
+	})
+	if err != nil {
+		return nil, err
+	}
+	t := findTable(md, table)
+	if t == nil {
+		return nil, unknownTable(md, table)
+	}
+	if name := missing(t); name != "" {
+		return nil, t.unknownColumn(name)
+	}
+	return t, nil
+}
+
+// Column returns the column with the given name or key, or nil. Names are
+// matched exactly first, then case-insensitively.
+func (t *Table) Column(name string) *Column {
+	for i := range t.Columns {
+		if t.Columns[i].Name == name || t.Columns[i].Key == name {
+			return &t.Columns[i]
+		}
+	}
+	for i := range t.Columns {
+		if strings.EqualFold(t.Columns[i].Name, name) {
+			return &t.Columns[i]
+		}
+	}
+	return nil
+}
+
+// View returns the view with the given name, or nil.
+func (t *Table) View(name string) *View {
+	for i := range t.Views {
+		if t.Views[i].Name == name {
+			return &t.Views[i]
+		}
+	}
+	for i := range t.Views {
+		if strings.EqualFold(t.Views[i].Name, name) {
+			return &t.Views[i]
+		}
+	}
+	return nil
+}
+
+// VisibleColumns returns the columns shown in view, or all columns when view
+// is empty.
+func (t *Table) VisibleColumns(view string) ([]Column, error) {
+	if strings.TrimSpace(view) == "" {
+		return t.Columns, nil
+	}
+	v := t.View(view)
+	if v == nil {
+		names := make([]string, len(t.Views))
+		for i, v := range t.Views {
+			names[i] = v.Name
+		}
+		return nil, &NameError{Kind: "view", Name: view, Table: t.Name, Suggestions: suggest(view, names)}
+	}
+	hidden := make(map[string]bool, len(v.HiddenColumns))
+	for _, key := range v.HiddenColumns {
+		hidden[key] = true
+	}
+	cols := make([]Column, 0, len(t.Columns))
+	for _, col := range t.Columns {
+		if !hidden[col.Key] {
+			cols = append(cols, col)
+		}
+	}
+	return cols, nil
+}
+
+func (t *Table) unknownColumn(name string) error {
+	names := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		names[i] = col.Name
+	}
+	return &NameError{Kind: "column", Name: name, Table: t.Name, Suggestions: suggest(name, names)}
+}
+
+func unknownTable(md *Metadata, name string) error {
+	names := make([]string, len(md.Tables))
+	for i, t := range md.Tables {
+		names[i] = t.Name
+	}
+	return &NameError{Kind: "table", Name: name, Suggestions: suggest(name, names)}
+}
+
+func findTable(md *Metadata, name string) *Table {
+	if t := md.Table(name); t != nil {
+		return t
+	}
+	for i := range md.Tables {
+		if strings.EqualFold(md.Tables[i].Name, name) || md.Tables[i].ID == name {
+			return &md.Tables[i]
+		}
+	}
+	return nil
+}
+
+// suggest returns the candidates close to name, closest first.
+func suggest(name string, candidates []string) []string {
+	type match struct {
+		name string
+		dist int
+	}
+	lower := strings.ToLower(name)
+	maxDist := len([]rune(name))/3 + 1
+	var matches []match
+	for _, cand := range candidates {
+		d := levenshtein(lower, strings.ToLower(cand))
+		if d <= maxDist || strings.Contains(strings.ToLower(cand), lower) {
+			matches = append(matches, match{cand, d})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].dist < matches[j].dist })
+	out := make([]string, 0, len(matches))
+	for _, m := range matches {
+		out = append(out, m.name)
+	}
+	return out
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,228 @@
+package seatable
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxSQLLimit is the largest LIMIT SeaTable accepts in a SELECT.
+const maxSQLLimit = 10000
+
+// ErrSQL is returned, wrapped with the server's message, when SeaTable
+// answers a generated statement with success set to false.
+var ErrSQL = errors.New("seatable: SQL query failed")
+
+// SQLOptions configures a QuerySQL call.
+type SQLOptions struct {
+	// Params are bound to the ? placeholders of the statement in order.
+	Params []any
+	// Named are bound to :name placeholders. See BindParams.
+	Named       map[string]any
+	ConvertKeys bool
+}
+
+// SQLResult is the answer to a SQL statement. Metadata describes the columns
+// of Results.
+type SQLResult struct {
+	Success      bool     `json:"success"`
+	ErrorMessage string   `json:"error_message,omitempty"`
+	Results      []Row    `json:"results"`
+	Metadata     []Column `json:"metadata,omitempty"`
+}
+
+// Err returns an error wrapping ErrSQL when the server reported the
+// statement as failed, and nil otherwise.
+func (r *SQLResult) Err() error {
+	if r.Success || r.ErrorMessage == "" {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrSQL, r.ErrorMessage)
+}
+
+// QuerySQL runs a SQL statement against the base. Statements that change the
+// schema invalidate the cached metadata.
+func (c *Client) QuerySQL(ctx context.Context, query string, opts *SQLOptions) (*SQLResult, *Response, error) {
+	if opts == nil {
+		opts = &SQLOptions{}
+	}
+	params := opts.Params
+	if opts.Named != nil || hasList(params) {
+		var err error
+		if query, params, err = BindParams(query, params, opts.Named); err != nil {
+			return nil, nil, err
+		}
+	}
+	body := map[string]any{
+		"sql":          query,
+		"convert_keys": opts.ConvertKeys,
+	}
+	if len(params) > 0 {
+		body["params"] = params
+	}
+
+	var out SQLResult
+	resp, err := c.do(ctx, "POST", c.endpoint(endpointSQL), body, &out)
+	if isSchemaStatement(query) {
+		c.InvalidateSchema()
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+	if out.Results == nil {
+		out.Results = []Row{}
+	}
+	return &out, resp, nil
+}
+
+// hasList reports whether any of params needs list expansion.
+func hasList(params []any) bool {
+	for _, p := range params {
+		if _, ok := expandList(p); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isSchemaStatement reports whether query is a DDL statement.
+func isSchemaStatement(query string) bool {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return false
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "ALTER", "CREATE", "DROP", "RENAME":
+		return true
+	}
+	return false
+}
+
+// QuoteIdent quotes a table or column name for use in a SQL statement.
+func QuoteIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+var (
+	selectPattern  = regexp.MustCompile(`(?is)^\s*SELECT\b`)
+	limitPattern   = regexp.MustCompile(`(?is)\s+LIMIT\s+(\d+)(?:\s*,\s*(\d+))?(?:\s+OFFSET\s+(\d+))?\s*;?\s*$`)
+	orderByPattern = regexp.MustCompile(`(?i)\bORDER\s+BY\b`)
+	groupByPattern = regexp.MustCompile(`(?i)\bGROUP\s+BY\b`)
+	// aggregatePattern matches selects that do not return table rows as-is.
+	aggregatePattern = regexp.MustCompile(`(?is)^\s*SELECT\s+DISTINCT\b|\b(?:COUNT|SUM|AVG|MIN|MAX)\s*\(`)
+)
+
+// QuerySQLAll runs a SELECT page by page so results are not cut off at the
+// server's row limit. A trailing LIMIT/OFFSET in query is honoured; without
+// an ORDER BY the pages are ordered by _id so they stay stable. At most
+// maxRows rows are returned (0 means no cap) and truncated reports whether
+// more rows were available. Other statements, and DISTINCT, GROUP BY or
+// aggregate selects without an ORDER BY, run once through QuerySQL.
+//
+// When a page fails, the rows read so far are returned with the failing
+// response and its error.
+func (c *Client) QuerySQLAll(ctx context.Context, query string, opts *SQLOptions, maxRows int) (result *SQLResult, truncated bool, resp *Response, err error) {
+	if !selectPattern.MatchString(query) {
+		result, resp, err = c.QuerySQL(ctx, query, opts)
+		return result, false, resp, err
+	}
+	ordered := orderByPattern.MatchString(query)
+	if !ordered && (groupByPattern.MatchString(query) || aggregatePattern.MatchString(query)) {
+		// Ordering by _id would change what these queries mean, and
+		// without an order their pages are not stable.
+		result, resp, err = c.QuerySQL(ctx, query, opts)
+		if err == nil && maxRows > 0 && len(result.Results) > maxRows {
+			result.Results = result.Results[:maxRows]
+			truncated = true
+		}
+		return result, truncated, resp, err
+	}
+
+	base := strings.TrimRight(strings.TrimSpace(query), ";")
+	offset, limit := 0, -1
+	if m := limitPattern.FindStringSubmatch(base); m != nil {
+		base = base[:len(base)-len(m[0])]
+		limit, _ = strconv.Atoi(m[1])
+		switch {
+		case m[2] != "":
+			// LIMIT offset, count
+			offset = limit
+			limit, _ = strconv.Atoi(m[2])
+		case m[3] != "":
+			offset, _ = strconv.Atoi(m[3])
+		}
+	}
+	if !ordered {
+		base += " ORDER BY _id"
+	}
+
+	want := limit
+	if maxRows > 0 && (want < 0 || maxRows < want) {
+		want = maxRows
+	}
+
+	result = &SQLResult{Success: true, Results: []Row{}}
+	more := false
+	for want < 0 || len(result.Results) < want {
+		size := maxSQLLimit
+		if want >= 0 {
+			// Ask for one row more than needed to tell whether the cap cut
+			// the result short. When the server limit leaves no room for
+			// it, a full last page is followed by a one-row probe.
+			size = min(size, want-len(result.Results)+1)
+		}
+		page, pageResp, err := c.querySQLPage(ctx, base, size, offset, opts, result)
+		if pageResp != nil {
+			resp = pageResp
+		}
+		if err != nil || page == nil {
+			return result, false, resp, err
+		}
+		result.Results = append(result.Results, page.Results...)
+		offset += len(page.Results)
+		if len(page.Results) < size {
+			break
+		}
+		more = true
+	}
+
+	if want >= 0 && want != limit && more && len(result.Results) == want {
+		page, pageResp, err := c.querySQLPage(ctx, base, 1, offset, opts, result)
+		if pageResp != nil {
+			resp = pageResp
+		}
+		if err != nil || page == nil {
+			return result, false, resp, err
+		}
+		truncated = len(page.Results) > 0
+	}
+	if want >= 0 && len(result.Results) > want {
+		result.Results = result.Results[:want]
+		truncated = want != limit
+	}
+	if resp == nil {
+		resp = &Response{StatusCode: 200}
+	}
+	return result, truncated, resp, nil
+}
+
+// querySQLPage runs one page of a QuerySQLAll query. An unsuccessful page is
+// recorded on result and reported as a nil page with a nil error.
+func (c *Client) querySQLPage(ctx context.Context, base string, size, offset int, opts *SQLOptions, result *SQLResult) (*SQLResult, *Response, error) {
+	page, resp, err := c.QuerySQL(ctx, fmt.Sprintf("%s LIMIT %d OFFSET %d", base, size, offset), opts)
+	if err != nil {
+		return nil, resp, err
+	}
+	if !page.Success {
+		result.Success = false
+		result.ErrorMessage = page.ErrorMessage
+		return nil, resp, nil
+	}
+	if result.Metadata == nil {
+		result.Metadata = page.Metadata
+	}
+	return page, resp, nil
+}
@@ -0,0 +1,191 @@
+package seatable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// sqlServer serves the SQL endpoint for a table of total rows and records
+// the statements it receives.
+type sqlServer struct {
+	total int
+
+	mu      sync.Mutex
+	queries []string
+}
+
+var pagePattern = regexp.MustCompile(`LIMIT (\d+) OFFSET (\d+)$`)
+
+func (s *sqlServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		SQL string `json:"sql"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	s.queries = append(s.queries, body.SQL)
+	s.mu.Unlock()
+
+	rows := []Row{}
+	if m := pagePattern.FindStringSubmatch(body.SQL); m != nil {
+		limit, _ := strconv.Atoi(m[1])
+		offset, _ := strconv.Atoi(m[2])
+		if limit > maxSQLLimit {
+			http.Error(w, "limit too large", http.StatusBadRequest)
+			return
+		}
+		for i := offset; i < min(offset+limit, s.total); i++ {
+			rows = append(rows, Row{"_id": fmt.Sprintf("r%d", i)})
+		}
+	} else {
+		rows = append(rows, Row{"COUNT(*)": s.total})
+	}
+	json.NewEncoder(w).Encode(SQLResult{Success: true, Results: rows})
+}
+
+func newTestClient(t *testing.T, h http.Handler) *Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	c := NewClient(srv.URL, srv.Client())
+	c.BaseUUID = "base"
+	c.UseAccessToken("token")
+	return c
+}
+
+func TestQuerySQLAll(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     int
+		query     string
+		maxRows   int
+		rows      int
+		truncated bool
+		queries   int
+	}{
+		{"fits in one page", 50, "SELECT * FROM t", 0, 50, false, 1},
+		{"several pages", 25000, "SELECT * FROM t", 0, 25000, false, 3},
+		{"max rows below total", 25000, "SELECT * FROM t", 15000, 15000, true, 2},
+		{"max rows at page boundary with more", 25000, "SELECT * FROM t", 20000, 20000, true, 3},
+		{"max rows at page boundary exact", 20000, "SELECT * FROM t", 20000, 20000, false, 3},
+		{"max rows above total", 12000, "SELECT * FROM t", 20000, 12000, false, 2},
+		{"limit below max rows", 25000, "SELECT * FROM t LIMIT 20000", 30000, 20000, false, 2},
+		{"limit above max rows", 25000, "SELECT * FROM t LIMIT 22000", 20000, 20000, true, 3},
+		{"limit with offset", 25000, "SELECT * FROM t LIMIT 100 OFFSET 24950", 0, 50, false, 1},
+		{"aggregate runs once", 25000, "SELECT COUNT(*) FROM t", 0, 1, false, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &sqlServer{total: tt.total}
+			c := newTestClient(t, srv)
+
+			res, truncated, resp, err := c.QuerySQLAll(context.Background(), tt.query, nil, tt.maxRows)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp == nil || resp.StatusCode != 200 {
+				t.Errorf("resp = %+v, want status 200", resp)
+			}
+			if len(res.Results) != tt.rows {
+				t.Errorf("got %d rows, want %d", len(res.Results), tt.rows)
+			}
+			if truncated != tt.truncated {
+				t.Errorf("truncated = %v, want %v", truncated, tt.truncated)
+			}
+			if len(srv.queries) != tt.queries {
+				t.Errorf("sent %d queries, want %d: %q", len(srv.queries), tt.queries, srv.queries)
+			}
+		})
+	}
+}
+
+func TestQuerySQLAllOrder(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"SELECT * FROM t", "SELECT * FROM t ORDER BY _id LIMIT 10000 OFFSET 0"},
+		{"SELECT * FROM t ORDER BY name", "SELECT * FROM t ORDER BY name LIMIT 10000 OFFSET 0"},
+		{"SELECT DISTINCT name FROM t ORDER BY name", "SELECT DISTINCT name FROM t ORDER BY name LIMIT 10000 OFFSET 0"},
+		{"SELECT DISTINCT name FROM t", "SELECT DISTINCT name FROM t"},
+		{"SELECT name, COUNT(*) FROM t GROUP BY name", "SELECT name, COUNT(*) FROM t GROUP BY name"},
+		{"select sum(amount) from t", "select sum(amount) from t"},
+	}
+	for _, tt := range tests {
+		srv := &sqlServer{}
+		c := newTestClient(t, srv)
+		if _, _, _, err := c.QuerySQLAll(context.Background(), tt.query, nil, 0); err != nil {
+			t.Fatal(err)
+		}
+		if len(srv.queries) == 0 || srv.queries[0] != tt.want {
+			t.Errorf("%q sent %q, want %q", tt.query, srv.queries, tt.want)
+		}
+	}
+}
+
+func TestLimitPattern(t *testing.T) {
+	tests := []struct {
+		query  string
+		match  bool
+		groups [3]string
+	}{
+		{"SELECT * FROM t LIMIT 10", true, [3]string{"10", "", ""}},
+		{"SELECT * FROM t limit 10;", true, [3]string{"10", "", ""}},
+		{"SELECT * FROM t LIMIT 5, 10", true, [3]string{"5", "10", ""}},
+		{"SELECT * FROM t LIMIT 5,10 ;", true, [3]string{"5", "10", ""}},
+		{"SELECT * FROM t LIMIT 10 OFFSET 5", true, [3]string{"10", "", "5"}},
+		{"SELECT * FROM t\nLIMIT 10\nOFFSET 5", true, [3]string{"10", "", "5"}},
+		{"SELECT * FROM t", false, [3]string{}},
+		{"SELECT * FROM t WHERE a = 'LIMIT 10' AND b = 1", false, [3]string{}},
+		{"SELECT * FROM (SELECT * FROM t LIMIT 10) x WHERE a = 1", false, [3]string{}},
+	}
+	for _, tt := range tests {
+		m := limitPattern.FindStringSubmatch(tt.query)
+		if (m != nil) != tt.match {
+			t.Errorf("%q: match = %v, want %v", tt.query, m != nil, tt.match)
+			continue
+		}
+		if m != nil && [3]string{m[1], m[2], m[3]} != tt.groups {
+			t.Errorf("%q: groups = %q, want %q", tt.query, m[1:], tt.groups)
+		}
+	}
+}
+
+func TestQuerySQLAllLimit(t *testing.T) {
+	tests := []struct {
+		query   string
+		first   string
+		rows    int
+		firstID string
+	}{
+		{"SELECT * FROM t LIMIT 5, 10", "SELECT * FROM t ORDER BY _id LIMIT 11 OFFSET 5", 10, "r5"},
+		{"SELECT * FROM t LIMIT 10 OFFSET 5", "SELECT * FROM t ORDER BY _id LIMIT 11 OFFSET 5", 10, "r5"},
+		{"SELECT * FROM t ORDER BY name LIMIT 10;", "SELECT * FROM t ORDER BY name LIMIT 11 OFFSET 0", 10, "r0"},
+	}
+	for _, tt := range tests {
+		srv := &sqlServer{total: 100}
+		c := newTestClient(t, srv)
+		res, _, _, err := c.QuerySQLAll(context.Background(), tt.query, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(srv.queries) == 0 || srv.queries[0] != tt.first {
+			t.Errorf("%q sent %q, want %q", tt.query, srv.queries, tt.first)
+		}
+		if len(res.Results) != tt.rows {
+			t.Errorf("%q: got %d rows, want %d", tt.query, len(res.Results), tt.rows)
+		}
+		if len(res.Results) > 0 && res.Results[0].ID() != tt.firstID {
+			t.Errorf("%q: first row %s, want %s", tt.query, res.Results[0].ID(), tt.firstID)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package seatable
+
+import (
+	"fmt"
+	"strings"
+)
+
+// systemColumns are row fields every table has without listing them in its
+// metadata.
+var systemColumns = map[string]bool{
+	"_id":            true,
+	"_ctime":         true,
+	"_mtime":         true,
+	"_creator":       true,
+	"_last_modifier": true,
+}
+
+// comparisons are the operators Select.Where accepts.
+var comparisons = map[string]bool{
+	"=":        true,
+	"<>":       true,
+	"<":        true,
+	"<=":       true,
+	">":        true,
+	">=":       true,
+	"LIKE":     true,
+	"NOT LIKE": true,
+}
+
+// QuoteColumn resolves name against the table's columns and returns the
+// column name quoted for SQL. System fields such as _id are accepted as
+// they are. Unknown names fail with a *NameError.
+func (t *Table) QuoteColumn(name string) (string, error) {
+	if systemColumns[name] {
+		return QuoteIdent(name), nil
+	}
+	col := t.Column(name)
+	if col == nil {
+		return "", t.unknownColumn(name)
+	}
+	return QuoteIdent(col.Name), nil
+}
+
+// Select builds a SELECT statement on one table. Table and column names are
+// checked against the table's metadata and backtick-quoted; values always go
+// through ? placeholders. The first error is kept and returned by Build.
+type Select struct {
+	table  *Table
+	fields []string
+	conds  []string
+	join   string
+	params []any
+	order  []string
+	limit  int
+	offset int
+	err    error
+}
+
+// NewSelect starts a SELECT of columns from t, or of all columns when none
+// are given.
+func NewSelect(t *Table, columns ...string) *Select {
+	s := &Select{table: t, join: " AND "}
+	for _, c := range columns {
+		s.fields = append(s.fields, s.column(c))
+	}
+	return s
+}
+
+// Count selects COUNT(*) instead of columns.
+func (s *Select) Count() *Select {
+	s.fields = []string{"COUNT(*)"}
+	return s
+}
+
+// AnyOf joins the conditions with OR instead of AND.
+func (s *Select) AnyOf() *Select {
+	s.join = " OR "
+	return s
+}
+
+// Where adds "column op ?". op is one of =, <>, <, <=, >, >=, LIKE and
+// NOT LIKE.
+func (s *Select) Where(column, op string, value any) *Select {
+	return s.compare(s.column(column), op, value)
+}
+
+// WhereLower is Where on LOWER(column), for case-insensitive matches.
+func (s *Select) WhereLower(column, op string, value any) *Select {
+	return s.compare("LOWER("+s.column(column)+")", op, value)
+}
+
+// WhereNull adds "column IS NULL".
+func (s *Select) WhereNull(column string) *Select {
+	s.conds = append(s.conds, s.column(column)+" IS NULL")
+	return s
+}
+
+// WhereNotNull adds "column IS NOT NULL".
+func (s *Select) WhereNotNull(column string) *Select {
+	s.conds = append(s.conds, s.column(column)+" IS NOT NULL")
+	return s
+}
+
+// WhereFilter adds the condition compiled from f.
+func (s *Select) WhereFilter(f *Filter) *Select {
+	where, params, err := f.Where(s.table)
+	if err != nil {
+		s.fail(err)
+		return s
+	}
+	if where != "" {
+		s.conds = append(s.conds, where)
+		s.params = append(s.params, params...)
+	}
+	return s
+}
+
+// OrderBy adds a sort column.
+func (s *Select) OrderBy(column string, desc bool) *Select {
+	o := s.column(column)
+	if desc {
+		o += " DESC"
+	}
+	s.order = append(s.order, o)
+	return s
+}
+
+// Limit caps the number of rows; 0 means no LIMIT clause.
+func (s *Select) Limit(n int) *Select {
+	s.limit = n
+	return s
+}
+
+// Offset skips the first n rows.
+func (s *Select) Offset(n int) *Select {
+	s.offset = n
+	return s
+}
+
+// Build returns the statement and its parameters.
+func (s *Select) Build() (string, []any, error) {
+	if s.err != nil {
+		return "", nil, s.err
+	}
+	fields := "*"
+	if len(s.fields) > 0 {
+		fields = strings.Join(s.fields, ", ")
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "SELECT %s FROM %s", fields, QuoteIdent(s.table.Name))
+	switch len(s.conds) {
+	case 0:
+	case 1:
+		b.WriteString(" WHERE " + s.conds[0])
+	default:
+		b.WriteString(" WHERE " + strings.Join(s.conds, s.join))
+	}
+	if len(s.order) > 0 {
+		b.WriteString(" ORDER BY " + strings.Join(s.order, ", "))
+	}
+	if s.limit > 0 {
+		fmt.Fprintf(&b, " LIMIT %d", s.limit)
+	}
+	if s.offset > 0 {
+		fmt.Fprintf(&b, " OFFSET %d", s.offset)
+	}
+	return b.String(), s.params, nil
+}
+
+func (s *Select) compare(expr, op string, value any) *Select {
+	op = strings.ToUpper(strings.TrimSpace(op))
+	if !comparisons[op] {
+		s.fail(fmt.Errorf("seatable: unsupported SQL operator %q", op))
+		return s
+	}
+	s.conds = append(s.conds, expr+" "+op+" ?")
+	s.params = append(s.params, value)
+	return s
+}
+
+func (s *Select) column(name string) string {
+	quoted, err := s.table.QuoteColumn(name)
+	if err != nil {
+		s.fail(err)
+	}
+	return quoted
+}
+
+func (s *Select) fail(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
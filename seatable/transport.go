@@ -0,0 +1,133 @@
+package seatable
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRequestTimeout  = 30 * time.Second
+	uploadRequestTimeout   = 60 * time.Second
+	downloadRequestTimeout = 5 * time.Minute
+)
+
+// TransportOptions configures the HTTP transport shared by a client's requests.
+// A zero Timeout means 30 seconds.
+type TransportOptions struct {
+	Timeout            time.Duration
+	ProxyURL           string
+	CACertPath         string
+	InsecureSkipVerify bool
+	ClientCertPath     string
+	ClientKeyPath      string
+}
+
+// DefaultHTTPClient serves clients created without an HTTP client.
+var DefaultHTTPClient = mustHTTPClient(TransportOptions{})
+
+func mustHTTPClient(opts TransportOptions) *http.Client {
+	c, err := NewHTTPClient(opts)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// NewHTTPClient builds a keep-alive pooled HTTP client from opts.
+func NewHTTPClient(opts TransportOptions) (*http.Client, error) {
+	tr := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   16,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	if p := strings.TrimSpace(opts.ProxyURL); p != "" {
+		proxyURL, err := url.Parse(p)
+		if err != nil {
+			return nil, fmt.Errorf("parse proxy URL: %w", err)
+		}
+		tr.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig, err := newTLSConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	tr.TLSClientConfig = tlsConfig
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultRequestTimeout
+	}
+	return &http.Client{Transport: tr, Timeout: timeout}, nil
+}
+
+func newTLSConfig(opts TransportOptions) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	}
+
+	if path := strings.TrimSpace(opts.CACertPath); path != "" {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", path)
+		}
+		cfg.RootCAs = pool
+	}
+
+	certPath := strings.TrimSpace(opts.ClientCertPath)
+	keyPath := strings.TrimSpace(opts.ClientKeyPath)
+	if certPath != "" || keyPath != "" {
+		if certPath == "" || keyPath == "" {
+			return nil, fmt.Errorf("client certificate and key must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// HTTPClient returns the HTTP client used for API calls.
+func (c *Client) HTTPClient() *http.Client {
+	if c.httpClient == nil {
+		return DefaultHTTPClient
+	}
+	return c.httpClient
+}
+
+// transferClient returns an HTTP client sharing the API transport but with a
+// longer timeout suited to file uploads and downloads.
+func (c *Client) transferClient(timeout time.Duration) *http.Client {
+	api := c.HTTPClient()
+	if api.Timeout > timeout {
+		timeout = api.Timeout
+	}
+	return &http.Client{Transport: api.Transport, Timeout: timeout}
+}
@@ -0,0 +1,62 @@
+package seatable
+
+// Row is a table row keyed by column name, or by column key when rows are
+// requested without key conversion. System fields start with an underscore.
+type Row map[string]any
+
+// ID returns the row's _id.
+func (r Row) ID() string {
+	id, _ := r["_id"].(string)
+	return id
+}
+
+// Column describes a table column. Data holds type-specific settings such
+// as select options or number formats.
+type Column struct {
+	Key      string         `json:"key"`
+	Name     string         `json:"name"`
+	Type     string         `json:"type"`
+	Width    int            `json:"width,omitempty"`
+	Editable bool           `json:"editable,omitempty"`
+	Data     map[string]any `json:"data,omitempty"`
+}
+
+// View describes a table view.
+type View struct {
+	ID            string   `json:"_id"`
+	Name          string   `json:"name"`
+	Type          string   `json:"type,omitempty"`
+	IsLocked      bool     `json:"is_locked,omitempty"`
+	HiddenColumns []string `json:"hidden_columns,omitempty"`
+}
+
+// Table describes a table with its columns and views.
+type Table struct {
+	ID      string   `json:"_id"`
+	Name    string   `json:"name"`
+	Columns []Column `json:"columns"`
+	Views   []View   `json:"views,omitempty"`
+}
+
+// Metadata is the schema of a base.
+type Metadata struct {
+	Tables []Table `json:"tables"`
+}
+
+// Table returns the table with the given name, or nil.
+func (m *Metadata) Table(name string) *Table {
+	for i := range m.Tables {
+		if m.Tables[i].Name == name {
+			return &m.Tables[i]
+		}
+	}
+	return nil
+}
+
+// Attachment is the value stored in file and image columns.
+type Attachment struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
@@ -0,0 +1,314 @@
+package seatable
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrUpsertKey is returned when rows lack key values or share a key.
+var ErrUpsertKey = errors.New("seatable: invalid upsert key")
+
+// upsertLookupSize is the number of keys looked up per SQL query.
+const upsertLookupSize = 200
+
+// UpsertOptions configures an UpsertRows call.
+type UpsertOptions struct {
+	// KeyColumns identify a row. Rows whose key values match an existing row
+	// update it; all others are appended.
+	KeyColumns []string
+	// SkipUnchanged leaves matched rows alone when none of the given fields
+	// differ from the stored values.
+	SkipUnchanged bool
+	Batch         BatchOptions
+}
+
+// UpsertResult reports the outcome of UpsertRows. Failure and skipped
+// indexes refer to the input rows. Rows are skipped when the inserts failed
+// with StopOnError set, so the updates were never sent.
+type UpsertResult struct {
+	Inserted       int          `json:"inserted"`
+	Updated        int          `json:"updated"`
+	Unchanged      int          `json:"unchanged"`
+	Failed         int          `json:"failed"`
+	Skipped        int          `json:"skipped"`
+	InsertedIDs    []string     `json:"insertedIds"`
+	UpdatedIDs     []string     `json:"updatedIds"`
+	UnchangedIDs   []string     `json:"unchangedIds"`
+	Failures       []RowFailure `json:"failures"`
+	SkippedIndexes []int        `json:"skippedIndexes"`
+}
+
+// UpsertRows appends rows that have no match on opts.KeyColumns and updates
+// the ones that do. Existing rows are looked up in bulk with SQL, then the
+// inserts and updates are sent as chunked batches. Key columns are matched
+// by name or key like everywhere else; the rows are sent with their key
+// fields renamed to the column names.
+func (c *Client) UpsertRows(ctx context.Context, table string, rows []Row, opts *UpsertOptions) (*UpsertResult, error) {
+	if opts == nil || len(opts.KeyColumns) == 0 {
+		return nil, fmt.Errorf("%w: no key columns", ErrUpsertKey)
+	}
+	t, err := c.ResolveTable(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	keyCols, err := keyColumns(t, opts.KeyColumns)
+	if err != nil {
+		return nil, err
+	}
+	rows = rekeyRows(rows, keyCols)
+
+	keys := make([]string, len(rows))
+	seen := make(map[string]int, len(rows))
+	for i, row := range rows {
+		k, err := rowKey(row, keyCols)
+		if err != nil {
+			return nil, fmt.Errorf("%w: row %d: %v", ErrUpsertKey, i, err)
+		}
+		if j, ok := seen[k]; ok {
+			return nil, fmt.Errorf("%w: rows %d and %d have the same key", ErrUpsertKey, j, i)
+		}
+		seen[k] = i
+		keys[i] = k
+	}
+
+	existing, err := c.lookupRows(ctx, t, rows, keyCols, opts.SkipUnchanged)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UpsertResult{
+		InsertedIDs:    []string{},
+		UpdatedIDs:     []string{},
+		UnchangedIDs:   []string{},
+		Failures:       []RowFailure{},
+		SkippedIndexes: []int{},
+	}
+	var (
+		inserts   []Row
+		insertIdx []int
+		updates   []RowUpdate
+		updateIdx []int
+	)
+	for i, row := range rows {
+		match, ok := existing[keys[i]]
+		switch {
+		case !ok:
+			inserts = append(inserts, row)
+			insertIdx = append(insertIdx, i)
+		case opts.SkipUnchanged && !rowChanged(row, match):
+			result.Unchanged++
+			result.UnchangedIDs = append(result.UnchangedIDs, match.ID())
+		default:
+			updates = append(updates, RowUpdate{RowID: match.ID(), Row: row})
+			updateIdx = append(updateIdx, i)
+		}
+	}
+
+	if len(inserts) > 0 {
+		res, err := c.AppendRowsChunked(ctx, table, inserts, &opts.Batch)
+		if res != nil {
+			result.Inserted = res.Succeeded
+			result.InsertedIDs = append(result.InsertedIDs, res.RowIDs...)
+			result.addFailures(res, insertIdx)
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+	if len(updates) > 0 {
+		if opts.Batch.StopOnError && result.Failed > 0 {
+			result.Skipped = len(updates)
+			result.SkippedIndexes = append(result.SkippedIndexes, updateIdx...)
+			return result, nil
+		}
+		res, err := c.UpdateRowsChunked(ctx, table, updates, &opts.Batch)
+		if res != nil {
+			result.Updated = res.Succeeded
+			result.UpdatedIDs = append(result.UpdatedIDs, res.RowIDs...)
+			result.addFailures(res, updateIdx)
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// addFailures maps the failures of a batch back to input rows.
+func (r *UpsertResult) addFailures(res *BatchResult, index []int) {
+	r.Failed += res.Failed
+	for _, f := range res.Failures {
+		f.Index = index[f.Index]
+		r.Failures = append(r.Failures, f)
+	}
+}
+
+// lookupRows finds the stored rows matching the keys of rows, keyed by
+// rowKey. When several rows share a key the first one wins.
+func (c *Client) lookupRows(ctx context.Context, t *Table, rows []Row, keyCols []*Column, allFields bool) (map[string]Row, error) {
+	var fields []string
+	if !allFields {
+		fields = []string{"_id"}
+		for _, col := range keyCols {
+			fields = append(fields, col.Name)
+		}
+	}
+
+	existing := make(map[string]Row)
+	for start := 0; start < len(rows); start += upsertLookupSize {
+		chunk := rows[start:min(start+upsertLookupSize, len(rows))]
+
+		match := &Filter{Conjunction: "or"}
+		for _, row := range chunk {
+			key := Filter{Conjunction: "and"}
+			for _, col := range keyCols {
+				key.Filters = append(key.Filters, Filter{Column: col.Name, Operator: "is", Value: keyValue(col, row[col.Name])})
+			}
+			match.Filters = append(match.Filters, key)
+		}
+		query, params, err := NewSelect(t, fields...).WhereFilter(match).Limit(maxSQLLimit).Build()
+		if err != nil {
+			return nil, err
+		}
+
+		res, _, err := c.QuerySQL(ctx, query, &SQLOptions{Params: params, ConvertKeys: true})
+		if err != nil {
+			return nil, err
+		}
+		if err := res.Err(); err != nil {
+			return nil, err
+		}
+		for _, row := range res.Results {
+			k, err := rowKey(row, keyCols)
+			if err != nil {
+				continue
+			}
+			if _, ok := existing[k]; !ok {
+				existing[k] = row
+			}
+		}
+	}
+	return existing, nil
+}
+
+// keyColumns resolves the key column names against t.
+func keyColumns(t *Table, names []string) ([]*Column, error) {
+	cols := make([]*Column, len(names))
+	for i, name := range names {
+		if systemColumns[name] {
+			cols[i] = &Column{Name: name, Key: name, Type: ColumnText}
+			continue
+		}
+		if cols[i] = t.Column(name); cols[i] == nil {
+			return nil, t.unknownColumn(name)
+		}
+	}
+	return cols, nil
+}
+
+// rekeyRows returns rows with key fields given under another spelling, such
+// as the column key or a different case, moved to the column name. Rows are
+// copied only when they change.
+func rekeyRows(rows []Row, keyCols []*Column) []Row {
+	out := make([]Row, len(rows))
+	for i, row := range rows {
+		out[i] = row
+		copied := false
+		for _, col := range keyCols {
+			if _, ok := out[i][col.Name]; ok {
+				continue
+			}
+			for k, v := range out[i] {
+				if k != col.Key && !strings.EqualFold(k, col.Name) {
+					continue
+				}
+				if !copied {
+					out[i] = make(Row, len(row))
+					for f, fv := range row {
+						out[i][f] = fv
+					}
+					copied = true
+				}
+				delete(out[i], k)
+				out[i][col.Name] = v
+				break
+			}
+		}
+	}
+	return out
+}
+
+// rowKey encodes the key column values of row so that equal keys compare
+// equal regardless of how numbers were decoded or typed.
+func rowKey(row Row, keyCols []*Column) (string, error) {
+	vals := make([]string, len(keyCols))
+	for i, col := range keyCols {
+		v, ok := row[col.Name]
+		if !ok || v == nil || v == "" {
+			return "", fmt.Errorf("key column %q is empty", col.Name)
+		}
+		switch n := keyValue(col, v).(type) {
+		case float64:
+			vals[i] = strconv.FormatFloat(n, 'f', -1, 64)
+		default:
+			vals[i] = fmt.Sprint(n)
+		}
+	}
+	b, err := json.Marshal(vals)
+	return string(b), err
+}
+
+// keyValue returns v in the form it is compared in: numbers as float64, and
+// numeric strings as numbers when the column holds numbers.
+func keyValue(col *Column, v any) any {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case float32:
+		return float64(n)
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case json.Number:
+		if f, err := n.Float64(); err == nil {
+			return f
+		}
+	case string:
+		if col.Type == ColumnNumber {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(n), 64); err == nil {
+				return f
+			}
+		}
+	}
+	return v
+}
+
+// rowChanged reports whether any field of row differs from stored.
+func rowChanged(row, stored Row) bool {
+	for k, v := range row {
+		if !reflect.DeepEqual(normalizeValue(v), normalizeValue(stored[k])) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeValue brings a value into the shape it would have after a JSON
+// round trip, so numbers and nested values compare equal.
+func normalizeValue(v any) any {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if err := json.Unmarshal(b, &out); err != nil {
+		return v
+	}
+	return out
+}
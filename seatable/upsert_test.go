@@ -0,0 +1,34 @@
+package seatable
+
+import "testing"
+
+func TestRowKey(t *testing.T) {
+	table := &Table{Name: "Contacts", Columns: []Column{
+		{Name: "Email", Key: "0000", Type: ColumnText},
+		{Name: "Number", Key: "0001", Type: ColumnNumber},
+		{Name: "Code", Key: "0002", Type: ColumnText},
+	}}
+	cols, err := keyColumns(table, []string{"email", "Number", "0002"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored := Row{"Email": "a@example.com", "Number": float64(1000000), "Code": "007"}
+	input := rekeyRows([]Row{{"email": "a@example.com", "Number": "1000000", "0002": "007"}}, cols)[0]
+	want, err := rowKey(stored, cols)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := rowKey(input, cols)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("rowKey(input) = %s, want %s", got, want)
+	}
+
+	other, _ := rowKey(Row{"Email": "a@example.com", "Number": 1e6, "Code": "7"}, cols)
+	if other == want {
+		t.Errorf("text key %q matched %q", "7", "007")
+	}
+}
@@ -0,0 +1,104 @@
+package seatable
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// User is a user related to the base. Email is the internal user id stored
+// in collaborator, creator and last-modifier columns (often ending in
+// @auth.local); ContactEmail is the address the user can be reached at.
+type User struct {
+	Email        string `json:"email"`
+	Name         string `json:"name"`
+	ContactEmail string `json:"contact_email"`
+	AvatarURL    string `json:"avatar_url,omitempty"`
+}
+
+// RelatedUsers lists the collaborators of the base and the users that
+// created or modified its rows.
+func (c *Client) RelatedUsers(ctx context.Context) ([]User, *Response, error) {
+	var out struct {
+		UserList []User `json:"user_list"`
+	}
+	resp, err := c.do(ctx, "GET", c.endpoint(endpointRelatedUsers), nil, &out)
+	if err != nil {
+		return nil, resp, err
+	}
+	if out.UserList == nil {
+		out.UserList = []User{}
+	}
+	return out.UserList, resp, nil
+}
+
+// UserEmails maps the user ids of the base to contact emails, falling back
+// to the id when a user has none. The map is cached next to the schema and
+// must not be modified.
+func (c *Client) UserEmails(ctx context.Context) (map[string]string, error) {
+	ttl := c.SchemaTTL
+	if ttl <= 0 {
+		ttl = DefaultSchemaTTL
+	}
+	c.schema.mu.Lock()
+	emails, fetched := c.schema.users, c.schema.usersFetched
+	c.schema.mu.Unlock()
+	if emails != nil && time.Since(fetched) < ttl {
+		return emails, nil
+	}
+
+	users, _, err := c.RelatedUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	emails = make(map[string]string, len(users))
+	for _, u := range users {
+		emails[u.Email] = u.Email
+		if u.ContactEmail != "" {
+			emails[u.Email] = u.ContactEmail
+		}
+	}
+
+	c.schema.mu.Lock()
+	defer c.schema.mu.Unlock()
+	c.schema.users, c.schema.usersFetched = emails, time.Now()
+	return emails, nil
+}
+
+// ColumnDecoder returns a Decoder for columns that maps user ids to contact
+// emails. Users are only looked up when a column holds them; when the server
+// refuses the lookup, user ids are kept as they are.
+func (c *Client) ColumnDecoder(ctx context.Context, columns []Column) (*Decoder, error) {
+	d := NewDecoder(columns)
+	if !hasUserColumn(columns) {
+		return d, nil
+	}
+	emails, err := c.UserEmails(ctx)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return d, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return d.WithUsers(emails), nil
+}
+
+// hasUserColumn reports whether any of columns stores user ids.
+func hasUserColumn(columns []Column) bool {
+	for i := range columns {
+		if isUserColumn(&columns[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// isUserColumn reports whether col stores user ids.
+func isUserColumn(col *Column) bool {
+	switch col.Type {
+	case ColumnCollaborator, ColumnCreator, ColumnLastModifier:
+		return true
+	}
+	return false
+}
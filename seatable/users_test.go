@@ -0,0 +1,53 @@
+package seatable
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestColumnDecoderUsers(t *testing.T) {
+	calls := 0
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api-gateway/api/v2/dtables/base/related-users/" {
+			http.NotFound(w, r)
+			return
+		}
+		calls++
+		json.NewEncoder(w).Encode(map[string]any{"user_list": []User{
+			{Email: "a1@auth.local", Name: "Ann", ContactEmail: "ann@example.com"},
+			{Email: "b2@auth.local", Name: "Bob"},
+		}})
+	}))
+	columns := []Column{
+		{Key: "0000", Name: "Owners", Type: ColumnCollaborator},
+		{Key: "0001", Name: "Created By", Type: ColumnCreator},
+	}
+
+	for i := 0; i < 2; i++ {
+		d, err := c.ColumnDecoder(context.Background(), columns)
+		if err != nil {
+			t.Fatal(err)
+		}
+		row := d.Row(Row{
+			"Owners":     []any{"a1@auth.local", "b2@auth.local", "c3@auth.local"},
+			"Created By": "a1@auth.local",
+		})
+		want := Row{
+			"Owners":     []string{"ann@example.com", "b2@auth.local", "c3@auth.local"},
+			"Created By": "ann@example.com",
+		}
+		if !reflect.DeepEqual(row, want) {
+			t.Errorf("row = %v, want %v", row, want)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("related users fetched %d times, want 1", calls)
+	}
+
+	if _, err := c.ColumnDecoder(context.Background(), []Column{{Key: "0002", Name: "Name", Type: ColumnText}}); err != nil {
+		t.Fatal(err)
+	}
+}
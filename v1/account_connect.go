@@ -0,0 +1,227 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/example/robomotion-seatable/seatable"
+	"github.com/robomotionio/robomotion-go/message"
+	"github.com/robomotionio/robomotion-go/runtime"
+)
+
+// SeaTableAccountConnect logs in with an account, looks a base up by name and
+// outputs a Client ID usable by every other SeaTable node.
+type SeaTableAccountConnect struct {
+	runtime.Node `spec:"id=Robomotion.SeaTable.AccountConnect,name=Account Connect,icon=mdiAccountKey,color=#00C2E0,inputs=1,outputs=1"`
+
+	InServer        runtime.InVariable[string] `spec:"title=Server URL,type=string,scope=Message,name=serverUrl,messageScope,customScope,jsScope"`
+	InWorkspaceName runtime.InVariable[string] `spec:"title=Workspace Name,type=string,scope=Message,name=workspaceName,messageScope,customScope,jsScope"`
+	InBaseName      runtime.InVariable[string] `spec:"title=Base Name,type=string,scope=Message,name=baseName,messageScope,customScope,jsScope"`
+
+	OptAuthMode     string                   `spec:"title=Authentication,value=password,enum=password|accountToken,enumNames=Email and Password|Account Token,option"`
+	OptLogin        runtime.Credential       `spec:"title=Login,scope=Custom,category=1,messageScope,customScope"`
+	OptAccountToken runtime.Credential       `spec:"title=Account Token,scope=Custom,category=4,messageScope,customScope"`
+	OptIdleTTL      runtime.OptVariable[int] `spec:"title=Idle Timeout (minutes),type=int,value=60,scope=Message,name=idleTimeout,messageScope,customScope,jsScope"`
+
+	OptMaxAttempts runtime.OptVariable[int]  `spec:"title=Max Attempts,type=int,value=3,scope=Message,name=maxAttempts,messageScope,customScope,jsScope"`
+	OptRetryDelay  runtime.OptVariable[int]  `spec:"title=Retry Base Delay (ms),type=int,value=500,scope=Message,name=retryDelay,messageScope,customScope,jsScope"`
+	OptRetryJitter runtime.OptVariable[bool] `spec:"title=Retry Jitter,type=bool,value=true,scope=Message,name=retryJitter,messageScope,customScope,jsScope"`
+	OptRetryPost   runtime.OptVariable[bool] `spec:"title=Retry POST Requests,type=bool,value=false,scope=Message,name=retryPost,messageScope,customScope,jsScope"`
+
+	OptRateLimit runtime.OptVariable[int] `spec:"title=Rate Limit (requests/min),type=int,value=0,scope=Message,name=rateLimit,messageScope,customScope,jsScope"`
+	OptSchemaTTL runtime.OptVariable[int] `spec:"title=Metadata Cache TTL (seconds),type=int,value=300,scope=Message,name=schemaTtl,messageScope,customScope,jsScope"`
+
+	OptRequestTimeout     runtime.OptVariable[int]    `spec:"title=Request Timeout (seconds),type=int,value=30,scope=Message,name=requestTimeout,messageScope,customScope,jsScope"`
+	OptProxyURL           runtime.OptVariable[string] `spec:"title=Proxy URL,type=string,scope=Message,name=proxyUrl,messageScope,customScope,jsScope"`
+	OptCACertPath         runtime.OptVariable[string] `spec:"title=CA Bundle Path,type=string,scope=Message,name=caCertPath,messageScope,customScope,jsScope"`
+	OptInsecureSkipVerify runtime.OptVariable[bool]   `spec:"title=Skip TLS Verification,type=bool,value=false,scope=Message,name=insecureSkipVerify,messageScope,customScope,jsScope"`
+	OptClientCertPath     runtime.OptVariable[string] `spec:"title=Client Certificate Path,type=string,scope=Message,name=clientCertPath,messageScope,customScope,jsScope"`
+	OptClientKeyPath      runtime.OptVariable[string] `spec:"title=Client Key Path,type=string,scope=Message,name=clientKeyPath,messageScope,customScope,jsScope"`
+	OptTimeout            runtime.OptVariable[int]    `spec:"title=Timeout (seconds),type=int,value=0,scope=Message,name=timeout,messageScope,customScope,jsScope"`
+
+	OutClientID   runtime.OutVariable[string] `spec:"title=Client ID,type=string,scope=Message,name=clientId,messageScope"`
+	OutBaseUUID   runtime.OutVariable[string] `spec:"title=Base UUID,type=string,scope=Message,name=baseUuid,messageScope"`
+	OutWorkspaces runtime.OutVariable[any]    `spec:"title=Workspaces,type=object,scope=Message,name=workspaces,messageScope"`
+
+	clients  ownedClients
+	requests nodeRequests
+}
+
+func (n *SeaTableAccountConnect) OnCreate() error { return nil }
+
+func (n *SeaTableAccountConnect) OnClose() error {
+	n.requests.cancelAll()
+	n.clients.releaseAll()
+	return nil
+}
+
+func (n *SeaTableAccountConnect) OnMessage(ctx message.Context) error {
+	server, err := n.InServer.Get(ctx)
+	if err != nil {
+		return err
+	}
+	server = seatable.TrimServerURL(server)
+	if server == "" {
+		return runtime.NewError("ErrInvalidArg", "Server URL is required")
+	}
+
+	workspaceName, err := n.InWorkspaceName.Get(ctx)
+	if err != nil {
+		return err
+	}
+	workspaceName = strings.TrimSpace(workspaceName)
+	baseName, err := n.InBaseName.Get(ctx)
+	if err != nil {
+		return err
+	}
+	baseName = strings.TrimSpace(baseName)
+	if baseName == "" {
+		return runtime.NewError("ErrInvalidArg", "Base Name is required")
+	}
+
+	opts := n.connectOptions()
+	httpClient, err := opts.httpClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	timeout, _ := n.OptTimeout.Get(ctx)
+	goCtx, done := n.requests.begin(timeout)
+	defer done()
+
+	accountToken, err := n.accountToken(goCtx, ctx, httpClient, server)
+	if err != nil {
+		return err
+	}
+
+	workspaces, _, err := seatable.ListWorkspaces(goCtx, httpClient, server, accountToken)
+	if err != nil {
+		return seaTableError(err)
+	}
+
+	ws, base, err := findAccountBase(workspaces, workspaceName, baseName)
+	if err != nil {
+		return err
+	}
+
+	client := seatable.NewClient(server, httpClient)
+	client.BaseUUID = base.UUID
+	opts.configure(ctx, client)
+	if err := client.UseAccountToken(goCtx, accountToken, ws.ID, base.Name); err != nil {
+		return seaTableError(err)
+	}
+	opts.applyRateLimit(ctx, client)
+	client.ResolveAPIFlavor(goCtx, seatable.APIFlavorAuto)
+
+	idleTTL, _ := n.OptIdleTTL.Get(ctx)
+	cfg := &SeaTableClient{
+		Client:  client,
+		IdleTTL: time.Duration(idleTTL) * time.Minute,
+	}
+
+	clientID := registerSeaTableClient(cfg)
+	n.clients.add(clientID)
+
+	summary := make([]any, 0, len(workspaces))
+	for _, w := range workspaces {
+		bases := make([]any, 0, len(w.Bases))
+		for _, t := range w.Bases {
+			bases = append(bases, map[string]any{"uuid": t.UUID, "name": t.Name})
+		}
+		summary = append(summary, map[string]any{
+			"id":    w.ID,
+			"name":  w.Name,
+			"bases": bases,
+		})
+	}
+
+	if err := n.OutClientID.Set(ctx, clientID); err != nil {
+		return err
+	}
+	n.OutBaseUUID.Set(ctx, cfg.BaseUUID)
+	n.OutWorkspaces.Set(ctx, summary)
+	return nil
+}
+
+// accountToken returns the account token from the vault, logging in with
+// email and password first when that mode is selected.
+func (n *SeaTableAccountConnect) accountToken(goCtx context.Context, ctx message.Context, httpClient *http.Client, server string) (string, error) {
+	if n.OptAuthMode == "accountToken" {
+		item, err := n.OptAccountToken.Get(ctx)
+		if err != nil {
+			return "", err
+		}
+		token, ok := item["value"].(string)
+		if !ok || strings.TrimSpace(token) == "" {
+			return "", runtime.NewError("ErrInvalidArg", "Invalid Account Token value")
+		}
+		return strings.TrimSpace(token), nil
+	}
+
+	item, err := n.OptLogin.Get(ctx)
+	if err != nil {
+		return "", err
+	}
+	username, _ := item["username"].(string)
+	password, _ := item["password"].(string)
+	if strings.TrimSpace(username) == "" || password == "" {
+		return "", runtime.NewError("ErrInvalidArg", "Login vault item requires username and password")
+	}
+	token, _, err := seatable.Login(goCtx, httpClient, server, strings.TrimSpace(username), password)
+	if err != nil {
+		return "", seaTableError(err)
+	}
+	return token, nil
+}
+
+// connectOptions returns the shared connection options of the node.
+func (n *SeaTableAccountConnect) connectOptions() connectOptions {
+	return connectOptions{
+		MaxAttempts:        &n.OptMaxAttempts,
+		RetryDelay:         &n.OptRetryDelay,
+		RetryJitter:        &n.OptRetryJitter,
+		RetryPost:          &n.OptRetryPost,
+		RateLimit:          &n.OptRateLimit,
+		SchemaTTL:          &n.OptSchemaTTL,
+		RequestTimeout:     &n.OptRequestTimeout,
+		ProxyURL:           &n.OptProxyURL,
+		CACertPath:         &n.OptCACertPath,
+		InsecureSkipVerify: &n.OptInsecureSkipVerify,
+		ClientCertPath:     &n.OptClientCertPath,
+		ClientKeyPath:      &n.OptClientKeyPath,
+	}
+}
+
+// findAccountBase locates a base by name, optionally restricted to one
+// workspace. Names are matched case-insensitively.
+func findAccountBase(workspaces []seatable.Workspace, workspaceName, baseName string) (*seatable.Workspace, *seatable.Base, error) {
+	var (
+		foundWS   *seatable.Workspace
+		foundBase *seatable.Base
+		matches   int
+	)
+	for i := range workspaces {
+		ws := &workspaces[i]
+		if workspaceName != "" && !strings.EqualFold(ws.Name, workspaceName) {
+			continue
+		}
+		for j := range ws.Bases {
+			if strings.EqualFold(ws.Bases[j].Name, baseName) {
+				foundWS, foundBase = ws, &ws.Bases[j]
+				matches++
+			}
+		}
+	}
+	switch {
+	case matches == 0 && workspaceName != "":
+		return nil, nil, runtime.NewError("ErrNotFound", fmt.Sprintf("Base %q not found in workspace %q", baseName, workspaceName))
+	case matches == 0:
+		return nil, nil, runtime.NewError("ErrNotFound", fmt.Sprintf("Base %q not found", baseName))
+	case matches > 1:
+		return nil, nil, runtime.NewError("ErrInvalidArg", fmt.Sprintf("Base name %q is ambiguous – set Workspace Name", baseName))
+	}
+	return foundWS, foundBase, nil
+}
@@ -2,10 +2,10 @@ package v1
 
 import (
     "context"
-    "encoding/json"
     "fmt"
     "strings"
 
+    "github.com/example/robomotion-seatable/seatable"
     "github.com/robomotionio/robomotion-go/message"
     "github.com/robomotionio/robomotion-go/runtime"
 )
@@ -25,16 +25,23 @@ type SeaTableAutoLink struct {
     OptMaxLeftRows runtime.OptVariable[int]  `spec:"title=Max Left Rows,type=int,value=1000,scope=Message,name=maxLeftRows,messageScope,customScope,jsScope"`
     OptMaxRightRows runtime.OptVariable[int] `spec:"title=Max Right Rows,type=int,value=1000,scope=Message,name=maxRightRows,messageScope,customScope,jsScope"`
     OptDryRun       runtime.OptVariable[bool] `spec:"title=Dry Run,type=bool,value=false,scope=Message,name=dryRun,messageScope,customScope,jsScope"`
+    OptTimeout runtime.OptVariable[int] `spec:"title=Timeout (seconds),type=int,value=0,scope=Message,name=timeout,messageScope,customScope,jsScope"`
 
     OutProcessedLeftRows runtime.OutVariable[int]    `spec:"title=Processed Left Rows,type=int,scope=Message,name=processedLeftRows,messageScope"`
     OutMatchedRows       runtime.OutVariable[int]    `spec:"title=Matched Left Rows,type=int,scope=Message,name=matchedLeftRows,messageScope"`
     OutCreatedLinks      runtime.OutVariable[int]    `spec:"title=Created Links,type=int,scope=Message,name=createdLinks,messageScope"`
     OutSkippedRows       runtime.OutVariable[int]    `spec:"title=Skipped (no match),type=int,scope=Message,name=skippedRows,messageScope"`
     OutMode              runtime.OutVariable[string] `spec:"title=Mode Used,type=string,scope=Message,name=mode,messageScope"`
+
+    requests nodeRequests
 }
 
 func (n *SeaTableAutoLink) OnCreate() error { return nil }
-func (n *SeaTableAutoLink) OnClose() error  { return nil }
+
+func (n *SeaTableAutoLink) OnClose() error {
+    n.requests.cancelAll()
+    return nil
+}
 
 func (n *SeaTableAutoLink) OnMessage(ctx message.Context) error {
     clientID, err := n.InClientID.Get(ctx)
@@ -86,12 +93,27 @@ func (n *SeaTableAutoLink) OnMessage(ctx message.Context) error {
     }
     dryRun, _ := n.OptDryRun.Get(ctx)
 
-    goCtx := context.Background()
+    timeout, _ := n.OptTimeout.Get(ctx)
+    goCtx, done := n.requests.begin(timeout)
+    defer done()
+
+    if tableName, err = resolveTableName(goCtx, cfg, tableName); err != nil {
+        return err
+    }
+    if otherTableName, err = resolveTableName(goCtx, cfg, otherTableName); err != nil {
+        return err
+    }
+    if err := validateColumnNames(goCtx, cfg, tableName, []string{leftKeyCol}); err != nil {
+        return err
+    }
+    if err := validateColumnNames(goCtx, cfg, otherTableName, []string{rightKeyCol}); err != nil {
+        return err
+    }
 
     // Fetch right table map[key] -> []row_id
-    rightRows, err := fetchRowsForKey(goCtx, cfg, otherTableName, rightKeyCol, maxRight)
+    rightRows, rightKeyCol, err := fetchRowsForKey(goCtx, cfg, otherTableName, rightKeyCol, maxRight)
     if err != nil {
-        return fmt.Errorf("fetch right table rows: %w", err)
+        return err
     }
     rightIndex := make(map[string][]string)
     for _, r := range rightRows {
@@ -106,9 +128,9 @@ func (n *SeaTableAutoLink) OnMessage(ctx message.Context) error {
         rightIndex[keyVal] = append(rightIndex[keyVal], rid)
     }
 
-    leftRows, err := fetchRowsForKey(goCtx, cfg, tableName, leftKeyCol, maxLeft)
+    leftRows, leftKeyCol, err := fetchRowsForKey(goCtx, cfg, tableName, leftKeyCol, maxLeft)
     if err != nil {
-        return fmt.Errorf("fetch left table rows: %w", err)
+        return err
     }
 
     processed := 0
@@ -117,6 +139,9 @@ func (n *SeaTableAutoLink) OnMessage(ctx message.Context) error {
     created := 0
 
     for _, row := range leftRows {
+        if err := goCtx.Err(); err != nil {
+            return err
+        }
         processed++
         leftRowID := getStringFromRow(row, "_id")
         if leftRowID == "" {
@@ -140,17 +165,9 @@ func (n *SeaTableAutoLink) OnMessage(ctx message.Context) error {
             continue
         }
 
-        url := fmt.Sprintf("%s/api-gateway/api/v2/dtables/%s/links/", cfg.Server, cfg.BaseUUID)
-        body := map[string]any{
-            "link_id":          linkID,
-            "table_name":       tableName,
-            "other_table_name": otherTableName,
-            "row_id":           leftRowID,
-            "other_rows_ids":   targets,
-        }
-        _, _, err := doSeaTableRequest(goCtx, "PUT", url, cfg.Token, body)
-        if err != nil {
-            return fmt.Errorf("update links for row %s: %w", leftRowID, err)
+        link := seatable.Link{ID: linkID, Table: tableName, OtherTable: otherTableName}
+        if _, err := cfg.UpdateLinks(goCtx, link, leftRowID, targets); err != nil {
+            return seaTableError(err)
         }
         created += len(targets)
     }
@@ -163,44 +180,32 @@ func (n *SeaTableAutoLink) OnMessage(ctx message.Context) error {
     return nil
 }
 
-// fetchRowsForKey uses SQL API to fetch _id and keyColumn.
-func fetchRowsForKey(ctx context.Context, cfg *SeaTableClient, tableName, keyColumn string, limit int) ([]map[string]any, error) {
-    sqlText := fmt.Sprintf("SELECT _id, %s FROM %s WHERE %s IS NOT NULL LIMIT %d", keyColumn, tableName, keyColumn, limit)
-    url := fmt.Sprintf("%s/api-gateway/api/v2/dtables/%s/sql/", cfg.Server, cfg.BaseUUID)
-    body := map[string]any{
-        "sql":          sqlText,
-        "convert_keys": true,
-    }
-    respBody, _, err := doSeaTableRequest(ctx, "POST", url, cfg.Token, body)
+// fetchRowsForKey uses SQL API to fetch _id and keyColumn. It also returns
+// the column name the rows are keyed by, which may differ from keyColumn in
+// case or be resolved from a column key.
+func fetchRowsForKey(ctx context.Context, cfg *SeaTableClient, tableName, keyColumn string, limit int) ([]seatable.Row, string, error) {
+    table, err := cfg.ResolveTable(ctx, tableName)
     if err != nil {
-        return nil, err
+        return nil, "", seaTableError(err)
     }
-
-    var parsed struct {
-        Results []map[string]any `json:"results"`
+    if col := table.Column(keyColumn); col != nil {
+        keyColumn = col.Name
     }
-    if err := json.Unmarshal(respBody, &parsed); err == nil && parsed.Results != nil {
-        return parsed.Results, nil
+    sqlText, params, err := seatable.NewSelect(table, "_id", keyColumn).WhereNotNull(keyColumn).Limit(limit).Build()
+    if err != nil {
+        return nil, "", seaTableError(err)
     }
-
-    // fallback
-    var alt map[string]any
-    if err := json.Unmarshal(respBody, &alt); err != nil {
-        return nil, err
+    result, _, err := cfg.QuerySQL(ctx, sqlText, &seatable.SQLOptions{Params: params, ConvertKeys: true})
+    if err != nil {
+        return nil, "", seaTableError(err)
     }
-    if arr, ok := alt["results"].([]any); ok {
-        rows := make([]map[string]any, 0, len(arr))
-        for _, v := range arr {
-            if m, ok := v.(map[string]any); ok {
-                rows = append(rows, m)
-            }
-        }
-        return rows, nil
+    if err := result.Err(); err != nil {
+        return nil, "", seaTableError(err)
     }
-    return nil, fmt.Errorf("unexpected SQL response for fetchRowsForKey")
+    return result.Results, keyColumn, nil
 }
 
-func getStringFromRow(row map[string]any, key string) string {
+func getStringFromRow(row seatable.Row, key string) string {
     if row == nil {
         return ""
     }
@@ -1,30 +1,54 @@
 package v1
 
 import (
-    "bytes"
     "context"
     "encoding/json"
+    "errors"
     "fmt"
-    "net/http"
     "strings"
     "sync"
+    "sync/atomic"
     "time"
+
+    "github.com/example/robomotion-seatable/seatable"
+    "github.com/robomotionio/robomotion-go/runtime"
 )
 
-// SeaTableClient holds connection info for a base.
+// SeaTableClient is a registered seatable.Client together with the state
+// the registry needs to evict it.
 type SeaTableClient struct {
-    Server   string
-    BaseUUID string
-    Token    string
+    *seatable.Client
+
+    // IdleTTL is how long the client may stay unused before it is evicted.
+    IdleTTL time.Duration
+
+    lastUsed atomic.Int64
+
+    cursorsMu sync.Mutex
+    cursors   map[string]*seatable.Cursor
 }
 
+const (
+    defaultClientIdleTTL = time.Hour
+    clientSweepInterval  = time.Minute
+)
+
 var (
     seaTableClients   = make(map[string]*SeaTableClient)
     seaTableClientsMu sync.RWMutex
+    clientSweeperOnce sync.Once
 )
 
 // registerSeaTableClient stores cfg and returns a clientId.
 func registerSeaTableClient(cfg *SeaTableClient) string {
+    clientSweeperOnce.Do(func() {
+        go sweepSeaTableClients(clientSweepInterval)
+    })
+    if cfg.IdleTTL <= 0 {
+        cfg.IdleTTL = defaultClientIdleTTL
+    }
+    cfg.lastUsed.Store(time.Now().UnixNano())
+
     seaTableClientsMu.Lock()
     defer seaTableClientsMu.Unlock()
     id := fmt.Sprintf("st-%s-%d", strings.ReplaceAll(cfg.BaseUUID, "-", ""), time.Now().UnixNano())
@@ -36,57 +60,200 @@ func getSeaTableClient(id string) (*SeaTableClient, bool) {
     seaTableClientsMu.RLock()
     defer seaTableClientsMu.RUnlock()
     cfg, ok := seaTableClients[id]
+    if ok {
+        cfg.lastUsed.Store(time.Now().UnixNano())
+    }
     return cfg, ok
 }
 
-func trimTrailingSlash(s string) string {
-    s = strings.TrimSpace(s)
-    return strings.TrimRight(s, "/")
+// unregisterSeaTableClient removes a client and wipes its tokens. It reports
+// whether the client was registered.
+func unregisterSeaTableClient(id string) bool {
+    seaTableClientsMu.Lock()
+    cfg, ok := seaTableClients[id]
+    delete(seaTableClients, id)
+    seaTableClientsMu.Unlock()
+
+    if ok {
+        cfg.Close()
+    }
+    return ok
 }
 
-// doSeaTableRequest marshals body (if not nil) and performs an HTTP request.
-func doSeaTableRequest(
-    ctx context.Context,
-    method string,
-    url string,
-    token string,
-    body interface{},
-) ([]byte, int, error) {
-    var buf *bytes.Reader
-    if body != nil {
-        b, err := json.Marshal(body)
-        if err != nil {
-            return nil, 0, fmt.Errorf("marshal request body: %w", err)
+// sweepSeaTableClients periodically evicts clients that have been idle for
+// longer than their IdleTTL.
+func sweepSeaTableClients(interval time.Duration) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+    for now := range ticker.C {
+        evictIdleSeaTableClients(now)
+    }
+}
+
+func evictIdleSeaTableClients(now time.Time) {
+    var evicted []*SeaTableClient
+
+    seaTableClientsMu.Lock()
+    for id, cfg := range seaTableClients {
+        if now.Sub(time.Unix(0, cfg.lastUsed.Load())) > cfg.IdleTTL {
+            delete(seaTableClients, id)
+            evicted = append(evicted, cfg)
         }
-        buf = bytes.NewReader(b)
+    }
+    seaTableClientsMu.Unlock()
+
+    for _, cfg := range evicted {
+        cfg.Close()
+    }
+}
+
+var cursorSeq atomic.Int64
+
+// addCursor stores cur with the client and returns its cursorId. Cursors are
+// dropped together with the client.
+func (c *SeaTableClient) addCursor(cur *seatable.Cursor) string {
+    c.cursorsMu.Lock()
+    defer c.cursorsMu.Unlock()
+    if c.cursors == nil {
+        c.cursors = make(map[string]*seatable.Cursor)
+    }
+    id := fmt.Sprintf("cur-%d", cursorSeq.Add(1))
+    c.cursors[id] = cur
+    return id
+}
+
+func (c *SeaTableClient) cursor(id string) (*seatable.Cursor, bool) {
+    c.cursorsMu.Lock()
+    defer c.cursorsMu.Unlock()
+    cur, ok := c.cursors[id]
+    return cur, ok
+}
+
+func (c *SeaTableClient) removeCursor(id string) {
+    c.cursorsMu.Lock()
+    defer c.cursorsMu.Unlock()
+    delete(c.cursors, id)
+}
+
+// ownedClients remembers the clients a connect node registered so they can be
+// released when the node closes.
+type ownedClients struct {
+    mu  sync.Mutex
+    ids []string
+}
+
+func (o *ownedClients) add(id string) {
+    o.mu.Lock()
+    defer o.mu.Unlock()
+    o.ids = append(o.ids, id)
+}
+
+func (o *ownedClients) releaseAll() {
+    o.mu.Lock()
+    ids := o.ids
+    o.ids = nil
+    o.mu.Unlock()
+
+    for _, id := range ids {
+        unregisterSeaTableClient(id)
+    }
+}
+
+// nodeRequests tracks the contexts of a node's in-flight messages so they can
+// be cancelled when the node closes (flow stop or redeploy).
+type nodeRequests struct {
+    mu      sync.Mutex
+    next    int
+    cancels map[int]context.CancelFunc
+}
+
+// begin returns the context for one message, bounded by timeoutSec when it
+// is positive. The returned function releases it and must always be called.
+func (r *nodeRequests) begin(timeoutSec int) (context.Context, func()) {
+    var (
+        ctx    context.Context
+        cancel context.CancelFunc
+    )
+    if timeoutSec > 0 {
+        ctx, cancel = context.WithTimeout(context.Background(), time.Duration(timeoutSec)*time.Second)
     } else {
-        buf = bytes.NewReader([]byte{})
+        ctx, cancel = context.WithCancel(context.Background())
     }
 
-    req, err := http.NewRequestWithContext(ctx, method, url, buf)
-    if err != nil {
-        return nil, 0, fmt.Errorf("create request: %w", err)
+    r.mu.Lock()
+    if r.cancels == nil {
+        r.cancels = make(map[int]context.CancelFunc)
     }
+    id := r.next
+    r.next++
+    r.cancels[id] = cancel
+    r.mu.Unlock()
 
-    if strings.TrimSpace(token) != "" {
-        req.Header.Set("Authorization", "Bearer "+token)
+    return ctx, func() {
+        r.mu.Lock()
+        delete(r.cancels, id)
+        r.mu.Unlock()
+        cancel()
     }
-    if body != nil {
-        req.Header.Set("Content-Type", "application/json")
+}
+
+// cancelAll aborts every in-flight message of the node.
+func (r *nodeRequests) cancelAll() {
+    r.mu.Lock()
+    cancels := r.cancels
+    r.cancels = nil
+    r.mu.Unlock()
+
+    for _, cancel := range cancels {
+        cancel()
     }
-    req.Header.Set("Accept", "application/json")
+}
 
-    client := &http.Client{Timeout: 30 * time.Second}
-    resp, err := client.Do(req)
-    if err != nil {
-        return nil, 0, fmt.Errorf("http request failed: %w", err)
+// resolveTableName checks a table name against the client's cached schema
+// and returns the table's exact name. Unknown names fail with a "did you
+// mean" suggestion; when the schema cannot be read the name is used as given
+// and the request itself reports any problem.
+func resolveTableName(ctx context.Context, cfg *SeaTableClient, name string) (string, error) {
+    t, err := cfg.ResolveTable(ctx, name)
+    var nameErr *seatable.NameError
+    switch {
+    case errors.As(err, &nameErr):
+        return "", seaTableError(err)
+    case err != nil:
+        return name, nil
     }
-    defer resp.Body.Close()
+    return t.Name, nil
+}
 
-    buf2 := new(bytes.Buffer)
-    if _, err := buf2.ReadFrom(resp.Body); err != nil {
-        return nil, resp.StatusCode, fmt.Errorf("read response body: %w", err)
+// validateColumnNames checks column names of a table like resolveTableName.
+func validateColumnNames(ctx context.Context, cfg *SeaTableClient, table string, names []string) error {
+    err := cfg.ValidateColumns(ctx, table, names)
+    var nameErr *seatable.NameError
+    if errors.As(err, &nameErr) {
+        return seaTableError(err)
     }
+    return nil
+}
 
-    return buf2.Bytes(), resp.StatusCode, nil
+// parseFilter reads a Filter option given as JSON text or as an object. A
+// missing or empty value means no filter.
+func parseFilter(v any) (*seatable.Filter, error) {
+    var data []byte
+    switch f := v.(type) {
+    case nil:
+        return nil, nil
+    case string:
+        data = []byte(f)
+    default:
+        b, err := json.Marshal(f)
+        if err != nil {
+            return nil, runtime.NewError("ErrInvalidArg", "Filter is not valid JSON")
+        }
+        data = b
+    }
+    filter, err := seatable.ParseFilter(data)
+    if err != nil {
+        return nil, seaTableError(err)
+    }
+    return filter, nil
 }
@@ -2,12 +2,16 @@ package v1
 
 import (
 	"strings"
+	"time"
 
+	"github.com/example/robomotion-seatable/seatable"
 	"github.com/robomotionio/robomotion-go/message"
 	"github.com/robomotionio/robomotion-go/runtime"
 )
 
 // SeaTableConnect creates a SeaTable client and outputs a Client ID for reuse.
+// It accepts either a long-lived API token, which is exchanged for a base
+// access token and refreshed automatically, or a base access token as-is.
 type SeaTableConnect struct {
 	runtime.Node `spec:"id=Robomotion.SeaTable.Connect,name=Connect,icon=mdiDatabase,color=#00C2E0,inputs=1,outputs=1"`
 
@@ -18,11 +22,41 @@ type SeaTableConnect struct {
 
 	OptBaseToken runtime.Credential `spec:"title=Base Token,scope=Custom,category=4,messageScope,customScope"`
 
+	OptTokenType string `spec:"title=Token Type,value=auto,enum=auto|apiToken|accessToken,enumNames=Auto Detect|API Token|Base Access Token,option"`
+
+	OptIdleTTL runtime.OptVariable[int] `spec:"title=Idle Timeout (minutes),type=int,value=60,scope=Message,name=idleTimeout,messageScope,customScope,jsScope"`
+
+	OptMaxAttempts runtime.OptVariable[int]  `spec:"title=Max Attempts,type=int,value=3,scope=Message,name=maxAttempts,messageScope,customScope,jsScope"`
+	OptRetryDelay  runtime.OptVariable[int]  `spec:"title=Retry Base Delay (ms),type=int,value=500,scope=Message,name=retryDelay,messageScope,customScope,jsScope"`
+	OptRetryJitter runtime.OptVariable[bool] `spec:"title=Retry Jitter,type=bool,value=true,scope=Message,name=retryJitter,messageScope,customScope,jsScope"`
+	OptRetryPost   runtime.OptVariable[bool] `spec:"title=Retry POST Requests,type=bool,value=false,scope=Message,name=retryPost,messageScope,customScope,jsScope"`
+
+	OptAPIFlavor string `spec:"title=API Flavor,value=auto,enum=auto|gateway|legacy,enumNames=Auto Detect|API Gateway (4.3+)|Legacy (dtable-server),option"`
+
+	OptRateLimit runtime.OptVariable[int] `spec:"title=Rate Limit (requests/min),type=int,value=0,scope=Message,name=rateLimit,messageScope,customScope,jsScope"`
+	OptSchemaTTL runtime.OptVariable[int] `spec:"title=Metadata Cache TTL (seconds),type=int,value=300,scope=Message,name=schemaTtl,messageScope,customScope,jsScope"`
+
+	OptRequestTimeout     runtime.OptVariable[int]    `spec:"title=Request Timeout (seconds),type=int,value=30,scope=Message,name=requestTimeout,messageScope,customScope,jsScope"`
+	OptProxyURL           runtime.OptVariable[string] `spec:"title=Proxy URL,type=string,scope=Message,name=proxyUrl,messageScope,customScope,jsScope"`
+	OptCACertPath         runtime.OptVariable[string] `spec:"title=CA Bundle Path,type=string,scope=Message,name=caCertPath,messageScope,customScope,jsScope"`
+	OptInsecureSkipVerify runtime.OptVariable[bool]   `spec:"title=Skip TLS Verification,type=bool,value=false,scope=Message,name=insecureSkipVerify,messageScope,customScope,jsScope"`
+	OptClientCertPath     runtime.OptVariable[string] `spec:"title=Client Certificate Path,type=string,scope=Message,name=clientCertPath,messageScope,customScope,jsScope"`
+	OptClientKeyPath      runtime.OptVariable[string] `spec:"title=Client Key Path,type=string,scope=Message,name=clientKeyPath,messageScope,customScope,jsScope"`
+	OptTimeout            runtime.OptVariable[int]    `spec:"title=Timeout (seconds),type=int,value=0,scope=Message,name=timeout,messageScope,customScope,jsScope"`
+
 	OutClientID runtime.OutVariable[string] `spec:"title=Client ID,type=string,scope=Message,name=clientId,messageScope"`
+
+	clients  ownedClients
+	requests nodeRequests
 }
 
 func (n *SeaTableConnect) OnCreate() error { return nil }
-func (n *SeaTableConnect) OnClose() error  { return nil }
+
+func (n *SeaTableConnect) OnClose() error {
+	n.requests.cancelAll()
+	n.clients.releaseAll()
+	return nil
+}
 
 func (n *SeaTableConnect) OnMessage(ctx message.Context) error {
 	server, err := n.InServer.Get(ctx)
@@ -34,46 +68,110 @@ func (n *SeaTableConnect) OnMessage(ctx message.Context) error {
 		return err
 	}
 
-	server = trimTrailingSlash(server)
+	server = seatable.TrimServerURL(server)
 	baseUUID = strings.TrimSpace(baseUUID)
 	if server == "" {
 		return runtime.NewError("ErrInvalidArg", "Server URL is required")
 	}
-	if baseUUID == "" {
-		return runtime.NewError("ErrInvalidArg", "Base UUID is required")
+
+	token, err := n.baseToken(ctx)
+	if err != nil {
+		return err
 	}
 
-	token := ""
-	if v, err := n.OptBaseTokenString.Get(ctx); err == nil {
-		token = strings.TrimSpace(v)
+	opts := n.connectOptions()
+	httpClient, err := opts.httpClient(ctx)
+	if err != nil {
+		return err
 	}
-	if token == "" {
-		item, err := n.OptBaseToken.Get(ctx)
-		if err != nil {
-			return err
-		}
-		if item == nil {
-			return runtime.NewError("ErrInvalidArg", "Base Token is required")
-		}
-		v, ok := item["value"]
-		if !ok {
-			return runtime.NewError("ErrInvalidArg", "Vault item missing 'value'")
+
+	tokenType := n.OptTokenType
+	if tokenType == "" || tokenType == "auto" {
+		tokenType = "apiToken"
+		if seatable.IsAccessToken(token) {
+			tokenType = "accessToken"
 		}
-		token, ok = v.(string)
-		if !ok || strings.TrimSpace(token) == "" {
-			return runtime.NewError("ErrInvalidArg", "Invalid Base Token value")
+	}
+
+	client := seatable.NewClient(server, httpClient)
+	client.BaseUUID = baseUUID
+	opts.configure(ctx, client)
+
+	timeout, _ := n.OptTimeout.Get(ctx)
+	goCtx, done := n.requests.begin(timeout)
+	defer done()
+
+	switch tokenType {
+	case "apiToken":
+		if err := client.UseAPIToken(goCtx, token); err != nil {
+			return seaTableError(err)
 		}
+
+	case "accessToken":
+		client.UseAccessToken(token)
+
+	default:
+		return runtime.NewError("ErrInvalidArg", "Unsupported Token Type")
 	}
 
+	if client.BaseUUID == "" {
+		return runtime.NewError("ErrInvalidArg", "Base UUID is required")
+	}
+	opts.applyRateLimit(ctx, client)
+	client.ResolveAPIFlavor(goCtx, n.OptAPIFlavor)
+
+	idleTTL, _ := n.OptIdleTTL.Get(ctx)
 	cfg := &SeaTableClient{
-		Server:   server,
-		BaseUUID: baseUUID,
-		Token:    token,
+		Client:  client,
+		IdleTTL: time.Duration(idleTTL) * time.Minute,
 	}
+
 	clientID := registerSeaTableClient(cfg)
+	n.clients.add(clientID)
 
 	if err := n.OutClientID.Set(ctx, clientID); err != nil {
 		return err
 	}
 	return nil
 }
+
+// connectOptions returns the shared connection options of the node.
+func (n *SeaTableConnect) connectOptions() connectOptions {
+	return connectOptions{
+		MaxAttempts:        &n.OptMaxAttempts,
+		RetryDelay:         &n.OptRetryDelay,
+		RetryJitter:        &n.OptRetryJitter,
+		RetryPost:          &n.OptRetryPost,
+		RateLimit:          &n.OptRateLimit,
+		SchemaTTL:          &n.OptSchemaTTL,
+		RequestTimeout:     &n.OptRequestTimeout,
+		ProxyURL:           &n.OptProxyURL,
+		CACertPath:         &n.OptCACertPath,
+		InsecureSkipVerify: &n.OptInsecureSkipVerify,
+		ClientCertPath:     &n.OptClientCertPath,
+		ClientKeyPath:      &n.OptClientKeyPath,
+	}
+}
+
+// baseToken reads the token from the text option, falling back to the vault.
+func (n *SeaTableConnect) baseToken(ctx message.Context) (string, error) {
+	if v, err := n.OptBaseTokenString.Get(ctx); err == nil && strings.TrimSpace(v) != "" {
+		return strings.TrimSpace(v), nil
+	}
+	item, err := n.OptBaseToken.Get(ctx)
+	if err != nil {
+		return "", err
+	}
+	if item == nil {
+		return "", runtime.NewError("ErrInvalidArg", "Base Token is required")
+	}
+	v, ok := item["value"]
+	if !ok {
+		return "", runtime.NewError("ErrInvalidArg", "Vault item missing 'value'")
+	}
+	token, ok := v.(string)
+	if !ok || strings.TrimSpace(token) == "" {
+		return "", runtime.NewError("ErrInvalidArg", "Invalid Base Token value")
+	}
+	return strings.TrimSpace(token), nil
+}
@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/example/robomotion-seatable/seatable"
+	"github.com/robomotionio/robomotion-go/message"
+	"github.com/robomotionio/robomotion-go/runtime"
+)
+
+// connectOptions points at the transport, retry and caching options that
+// Connect and Account Connect both declare. The SDK does not read embedded
+// structs, so each node keeps its own fields and hands them over here.
+type connectOptions struct {
+	MaxAttempts *runtime.OptVariable[int]
+	RetryDelay  *runtime.OptVariable[int]
+	RetryJitter *runtime.OptVariable[bool]
+	RetryPost   *runtime.OptVariable[bool]
+
+	RateLimit *runtime.OptVariable[int]
+	SchemaTTL *runtime.OptVariable[int]
+
+	RequestTimeout     *runtime.OptVariable[int]
+	ProxyURL           *runtime.OptVariable[string]
+	CACertPath         *runtime.OptVariable[string]
+	InsecureSkipVerify *runtime.OptVariable[bool]
+	ClientCertPath     *runtime.OptVariable[string]
+	ClientKeyPath      *runtime.OptVariable[string]
+}
+
+// httpClient builds the HTTP client from the transport options.
+func (o connectOptions) httpClient(ctx message.Context) (*http.Client, error) {
+	var opts seatable.TransportOptions
+	if v, err := o.RequestTimeout.Get(ctx); err == nil && v > 0 {
+		opts.Timeout = time.Duration(v) * time.Second
+	}
+	opts.ProxyURL, _ = o.ProxyURL.Get(ctx)
+	opts.CACertPath, _ = o.CACertPath.Get(ctx)
+	opts.InsecureSkipVerify, _ = o.InsecureSkipVerify.Get(ctx)
+	opts.ClientCertPath, _ = o.ClientCertPath.Get(ctx)
+	opts.ClientKeyPath, _ = o.ClientKeyPath.Get(ctx)
+
+	httpClient, err := seatable.NewHTTPClient(opts)
+	if err != nil {
+		return nil, runtime.NewError("ErrInvalidArg", err.Error())
+	}
+	return httpClient, nil
+}
+
+// retryPolicy builds the client's retry policy from the options.
+func (o connectOptions) retryPolicy(ctx message.Context) seatable.RetryPolicy {
+	policy := seatable.DefaultRetryPolicy()
+	if v, err := o.MaxAttempts.Get(ctx); err == nil && v > 0 {
+		policy.MaxAttempts = v
+	}
+	if v, err := o.RetryDelay.Get(ctx); err == nil && v > 0 {
+		policy.BaseDelay = time.Duration(v) * time.Millisecond
+	}
+	if v, err := o.RetryJitter.Get(ctx); err == nil {
+		policy.Jitter = v
+	}
+	if v, err := o.RetryPost.Get(ctx); err == nil {
+		policy.RetryPost = v
+	}
+	return policy
+}
+
+// configure applies the retry policy and the metadata cache TTL to client.
+func (o connectOptions) configure(ctx message.Context, client *seatable.Client) {
+	client.Retry = o.retryPolicy(ctx)
+	if v, err := o.SchemaTTL.Get(ctx); err == nil && v > 0 {
+		client.SchemaTTL = time.Duration(v) * time.Second
+	}
+}
+
+// applyRateLimit sets the rate limit once the client knows its base, as the
+// budget is shared per base.
+func (o connectOptions) applyRateLimit(ctx message.Context, client *seatable.Client) {
+	rateLimit, _ := o.RateLimit.Get(ctx)
+	client.SetRateLimit(rateLimit)
+}
@@ -0,0 +1,33 @@
+package v1
+
+import (
+	"strings"
+
+	"github.com/robomotionio/robomotion-go/message"
+	"github.com/robomotionio/robomotion-go/runtime"
+)
+
+// SeaTableDisconnect releases a client created by Connect and wipes its tokens.
+type SeaTableDisconnect struct {
+	runtime.Node `spec:"id=Robomotion.SeaTable.Disconnect,name=Disconnect,icon=mdiLanDisconnect,color=#00C2E0,inputs=1,outputs=1"`
+
+	InClientID runtime.InVariable[string] `spec:"title=Client ID,type=string,scope=Message,name=clientId,messageScope,jsScope,customScope"`
+}
+
+func (n *SeaTableDisconnect) OnCreate() error { return nil }
+func (n *SeaTableDisconnect) OnClose() error  { return nil }
+
+func (n *SeaTableDisconnect) OnMessage(ctx message.Context) error {
+	clientID, err := n.InClientID.Get(ctx)
+	if err != nil {
+		return err
+	}
+	clientID = strings.TrimSpace(clientID)
+	if clientID == "" {
+		return runtime.NewError("ErrInvalidArg", "Client ID is required")
+	}
+
+	// Disconnecting an already released or evicted client is not an error.
+	unregisterSeaTableClient(clientID)
+	return nil
+}
@@ -2,14 +2,10 @@ package v1
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/robomotionio/robomotion-go/message"
 	"github.com/robomotionio/robomotion-go/runtime"
@@ -23,15 +19,22 @@ type SeaTableDownloadFile struct {
 	InFilePath runtime.InVariable[string] `spec:"title=File Path (in SeaTable),type=string,scope=Message,name=filePath,messageScope,jsScope,customScope"`
 
 	OptSavePath runtime.OptVariable[string] `spec:"title=Save Path (local),type=string,scope=Message,name=savePath,messageScope,customScope,jsScope"`
+	OptTimeout  runtime.OptVariable[int]    `spec:"title=Timeout (seconds),type=int,value=0,scope=Message,name=timeout,messageScope,customScope,jsScope"`
 
-	OutStatusCode   runtime.OutVariable[int]    `spec:"title=Status Code,type=int,scope=Message,name=statusCode,messageScope"`
-	OutDownloadURL  runtime.OutVariable[string] `spec:"title=Download URL,type=string,scope=Message,name=downloadUrl,messageScope"`
-	OutSavedPath    runtime.OutVariable[string] `spec:"title=Saved Path,type=string,scope=Message,name=savedPath,messageScope"`
-	OutFileSize     runtime.OutVariable[int]    `spec:"title=File Size (bytes),type=int,scope=Message,name=fileSize,messageScope"`
+	OutStatusCode  runtime.OutVariable[int]    `spec:"title=Status Code,type=int,scope=Message,name=statusCode,messageScope"`
+	OutDownloadURL runtime.OutVariable[string] `spec:"title=Download URL,type=string,scope=Message,name=downloadUrl,messageScope"`
+	OutSavedPath   runtime.OutVariable[string] `spec:"title=Saved Path,type=string,scope=Message,name=savedPath,messageScope"`
+	OutFileSize    runtime.OutVariable[int]    `spec:"title=File Size (bytes),type=int,scope=Message,name=fileSize,messageScope"`
+
+	requests nodeRequests
 }
 
 func (n *SeaTableDownloadFile) OnCreate() error { return nil }
-func (n *SeaTableDownloadFile) OnClose() error  { return nil }
+
+func (n *SeaTableDownloadFile) OnClose() error {
+	n.requests.cancelAll()
+	return nil
+}
 
 func (n *SeaTableDownloadFile) OnMessage(ctx message.Context) error {
 	clientID, err := n.InClientID.Get(ctx)
@@ -55,19 +58,21 @@ func (n *SeaTableDownloadFile) OnMessage(ctx message.Context) error {
 	savePath, _ := n.OptSavePath.Get(ctx)
 	savePath = strings.TrimSpace(savePath)
 
-	goCtx := context.Background()
+	timeout, _ := n.OptTimeout.Get(ctx)
+	goCtx, done := n.requests.begin(timeout)
+	defer done()
 
 	// Step 1: Get download link
-	downloadURL, err := getDownloadLink(goCtx, cfg, filePath)
+	downloadURL, _, err := cfg.GetDownloadLink(goCtx, filePath)
 	if err != nil {
-		return err
+		return seaTableError(err)
 	}
 
 	n.OutDownloadURL.Set(ctx, downloadURL)
 
 	// Step 2: Download the file if savePath is provided
 	if savePath != "" {
-		fileSize, err := downloadAndSaveFile(goCtx, downloadURL, savePath)
+		fileSize, err := downloadAndSaveFile(goCtx, cfg, downloadURL, savePath)
 		if err != nil {
 			return err
 		}
@@ -84,32 +89,7 @@ func (n *SeaTableDownloadFile) OnMessage(ctx message.Context) error {
 	return nil
 }
 
-func getDownloadLink(ctx context.Context, cfg *SeaTableClient, filePath string) (string, error) {
-	// The API endpoint for getting download link
-	url := fmt.Sprintf("%s/api/v2.1/dtable/app-download-link/?path=%s", cfg.Server, filePath)
-	
-	respBody, status, err := doSeaTableRequest(ctx, "GET", url, cfg.Token, nil)
-	if err != nil {
-		return "", err
-	}
-	if status >= 300 {
-		return "", fmt.Errorf("get download link failed: status=%d body=%s", status, string(respBody))
-	}
-
-	var result map[string]any
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return "", fmt.Errorf("parse download link response: %w", err)
-	}
-
-	downloadLink, ok := result["download_link"].(string)
-	if !ok || downloadLink == "" {
-		return "", fmt.Errorf("download_link not found in response")
-	}
-
-	return downloadLink, nil
-}
-
-func downloadAndSaveFile(ctx context.Context, downloadURL, savePath string) (int, error) {
+func downloadAndSaveFile(ctx context.Context, cfg *SeaTableClient, downloadURL, savePath string) (int, error) {
 	// Ensure directory exists
 	dir := filepath.Dir(savePath)
 	if dir != "" && dir != "." {
@@ -118,23 +98,6 @@ func downloadAndSaveFile(ctx context.Context, downloadURL, savePath string) (int
 		}
 	}
 
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", downloadURL, nil)
-	if err != nil {
-		return 0, fmt.Errorf("create download request: %w", err)
-	}
-
-	client := &http.Client{Timeout: 5 * time.Minute}
-	resp, err := client.Do(req)
-	if err != nil {
-		return 0, fmt.Errorf("download request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 300 {
-		return 0, fmt.Errorf("download failed with status: %d", resp.StatusCode)
-	}
-
 	// Create output file
 	outFile, err := os.Create(savePath)
 	if err != nil {
@@ -142,12 +105,12 @@ func downloadAndSaveFile(ctx context.Context, downloadURL, savePath string) (int
 	}
 	defer outFile.Close()
 
-	// Copy content
-	written, err := io.Copy(outFile, resp.Body)
+	written, _, err := cfg.Download(ctx, downloadURL, outFile)
 	if err != nil {
-		return 0, fmt.Errorf("save file: %w", err)
+		outFile.Close()
+		os.Remove(savePath)
+		return 0, seaTableError(err)
 	}
 
 	return int(written), nil
 }
-
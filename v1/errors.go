@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/example/robomotion-seatable/seatable"
+	"github.com/robomotionio/robomotion-go/runtime"
+)
+
+var errClientClosed = runtime.NewError("ErrInvalidArg", "Unknown Client ID – run SeaTable.Connect first")
+
+// seaTableErrorCode maps an HTTP status to a runtime error code.
+func seaTableErrorCode(status int) string {
+	switch {
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return "ErrUnauthorized"
+	case status == http.StatusNotFound:
+		return "ErrNotFound"
+	case status == http.StatusTooManyRequests:
+		return "ErrRateLimited"
+	case status == http.StatusBadRequest, status == http.StatusConflict, status == http.StatusUnprocessableEntity:
+		return "ErrValidation"
+	case status >= 500:
+		return "ErrServer"
+	}
+	return "ErrHTTP"
+}
+
+// seaTableError converts an error from the seatable package into a runtime
+// error with a typed code.
+func seaTableError(err error) error {
+	var (
+		apiErr  *seatable.APIError
+		nameErr *seatable.NameError
+	)
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &nameErr):
+		return runtime.NewError("ErrNotFound", nameErrorMessage(nameErr))
+	case errors.As(err, &apiErr):
+		return runtime.NewError(seaTableErrorCode(apiErr.StatusCode), fmt.Sprintf("SeaTable returned %d: %s", apiErr.StatusCode, apiErr.Message))
+	case errors.Is(err, seatable.ErrClientClosed):
+		return errClientClosed
+	case errors.Is(err, seatable.ErrBaseMismatch):
+		return runtime.NewError("ErrInvalidArg", "API token does not belong to the given Base UUID")
+	case errors.Is(err, seatable.ErrUpsertKey), errors.Is(err, seatable.ErrInvalidFilter), errors.Is(err, seatable.ErrSQLParams):
+		return runtime.NewError("ErrInvalidArg", strings.TrimPrefix(err.Error(), "seatable: "))
+	case errors.Is(err, seatable.ErrSQL):
+		return runtime.NewError("ErrValidation", strings.TrimPrefix(err.Error(), "seatable: "))
+	}
+	return err
+}
+
+// checkSeaTableError returns the runtime error for err. HTTP errors are only
+// raised when fail is set, so nodes can report the status code instead.
+func checkSeaTableError(fail bool, err error) error {
+	var apiErr *seatable.APIError
+	if !fail && errors.As(err, &apiErr) {
+		return nil
+	}
+	return seaTableError(err)
+}
+
+func nameErrorMessage(e *seatable.NameError) string {
+	msg := fmt.Sprintf("Unknown %s %q", e.Kind, e.Name)
+	if e.Table != "" {
+		msg += fmt.Sprintf(" in table %q", e.Table)
+	}
+	if len(e.Suggestions) > 0 {
+		msg += fmt.Sprintf(" – did you mean %q?", e.Suggestions[0])
+	}
+	return msg
+}
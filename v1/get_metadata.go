@@ -1,28 +1,38 @@
 package v1
 
 import (
-	"context"
 	"encoding/json"
-	"fmt"
 
+	"github.com/example/robomotion-seatable/seatable"
 	"github.com/robomotionio/robomotion-go/message"
 	"github.com/robomotionio/robomotion-go/runtime"
 )
 
-// SeaTableGetMetadata retrieves the metadata (tables, columns structure) of a base.
+// SeaTableGetMetadata retrieves the metadata (tables, columns structure) of a
+// base from the client's metadata cache.
 type SeaTableGetMetadata struct {
 	runtime.Node `spec:"id=Robomotion.SeaTable.GetMetadata,name=Get Metadata,icon=mdiDatabaseCog,color=#00C2E0,inputs=1,outputs=1"`
 
 	InClientID runtime.InVariable[string] `spec:"title=Client ID,type=string,scope=Message,name=clientId,messageScope,jsScope,customScope"`
 
+	OptRefresh     runtime.OptVariable[bool] `spec:"title=Refresh Metadata,type=bool,value=false,scope=Message,name=refresh,messageScope,customScope,jsScope"`
+	OptFailOnError runtime.OptVariable[bool] `spec:"title=Fail on HTTP Error,type=bool,value=false,scope=Message,name=failOnError,messageScope,customScope,jsScope"`
+	OptTimeout     runtime.OptVariable[int]  `spec:"title=Timeout (seconds),type=int,value=0,scope=Message,name=timeout,messageScope,customScope,jsScope"`
+
 	OutStatusCode runtime.OutVariable[int]    `spec:"title=Status Code,type=int,scope=Message,name=statusCode,messageScope"`
 	OutRaw        runtime.OutVariable[string] `spec:"title=Raw Body,type=string,scope=Message,name=body,messageScope"`
 	OutJSON       runtime.OutVariable[any]    `spec:"title=JSON,type=object,scope=Message,name=json,messageScope"`
 	OutTables     runtime.OutVariable[any]    `spec:"title=Tables,type=object,scope=Message,name=tables,messageScope"`
+
+	requests nodeRequests
 }
 
 func (n *SeaTableGetMetadata) OnCreate() error { return nil }
-func (n *SeaTableGetMetadata) OnClose() error  { return nil }
+
+func (n *SeaTableGetMetadata) OnClose() error {
+	n.requests.cancelAll()
+	return nil
+}
 
 func (n *SeaTableGetMetadata) OnMessage(ctx message.Context) error {
 	clientID, err := n.InClientID.Get(ctx)
@@ -34,40 +44,33 @@ func (n *SeaTableGetMetadata) OnMessage(ctx message.Context) error {
 		return runtime.NewError("ErrInvalidArg", "Unknown Client ID – run SeaTable.Connect first")
 	}
 
-	url := fmt.Sprintf("%s/api-gateway/api/v2/dtables/%s/metadata/", cfg.Server, cfg.BaseUUID)
-	respBody, status, err := doSeaTableRequest(context.Background(), "GET", url, cfg.Token, nil)
-	if err != nil {
+	timeout, _ := n.OptTimeout.Get(ctx)
+	goCtx, done := n.requests.begin(timeout)
+	defer done()
+
+	// Metadata is cached per client; Refresh Metadata bypasses the cache.
+	if refresh, _ := n.OptRefresh.Get(ctx); refresh {
+		cfg.InvalidateSchema()
+	}
+	metadata, resp, err := cfg.CachedMetadata(goCtx)
+	failOnError, _ := n.OptFailOnError.Get(ctx)
+	if err := checkSeaTableError(failOnError, err); err != nil {
 		return err
 	}
 
-	n.OutStatusCode.Set(ctx, status)
-	n.OutRaw.Set(ctx, string(respBody))
+	n.OutStatusCode.Set(ctx, resp.StatusCode)
+	n.OutRaw.Set(ctx, string(resp.Body))
 
 	var parsed any
-	if err := json.Unmarshal(respBody, &parsed); err == nil {
+	if err := json.Unmarshal(resp.Body, &parsed); err == nil {
 		n.OutJSON.Set(ctx, parsed)
 	}
 
-	// Extract tables array from metadata
-	var tables any
-	if m, ok := parsed.(map[string]any); ok {
-		if metadata, ok := m["metadata"].(map[string]any); ok {
-			if t, ok := metadata["tables"]; ok {
-				tables = t
-			}
-		}
-		// Fallback: tables might be at root level
-		if tables == nil {
-			if t, ok := m["tables"]; ok {
-				tables = t
-			}
-		}
-	}
-	if tables == nil {
-		tables = []any{}
+	tables := []seatable.Table{}
+	if metadata != nil {
+		tables = metadata.Tables
 	}
 	n.OutTables.Set(ctx, tables)
 
 	return nil
 }
-
@@ -1,12 +1,10 @@
 package v1
 
 import (
-    "context"
     "encoding/json"
-    "fmt"
-    "net/url"
     "strings"
 
+    "github.com/example/robomotion-seatable/seatable"
     "github.com/robomotionio/robomotion-go/message"
     "github.com/robomotionio/robomotion-go/runtime"
 )
@@ -21,15 +19,24 @@ type SeaTableGetRow struct {
 
     OptViewName runtime.OptVariable[string] `spec:"title=View Name,type=string,scope=Message,name=viewName,messageScope,customScope,jsScope"`
     OptConvert  runtime.OptVariable[bool]   `spec:"title=Convert Keys,type=bool,value=true,scope=Message,name=convertKeys,messageScope,customScope,jsScope"`
+    OptTypedOutput runtime.OptVariable[bool] `spec:"title=Typed Output,type=bool,value=false,scope=Message,name=typedOutput,messageScope,customScope,jsScope"`
+    OptFailOnError runtime.OptVariable[bool] `spec:"title=Fail on HTTP Error,type=bool,value=false,scope=Message,name=failOnError,messageScope,customScope,jsScope"`
+    OptTimeout runtime.OptVariable[int] `spec:"title=Timeout (seconds),type=int,value=0,scope=Message,name=timeout,messageScope,customScope,jsScope"`
 
     OutStatusCode runtime.OutVariable[int]    `spec:"title=Status Code,type=int,scope=Message,name=statusCode,messageScope"`
     OutRaw        runtime.OutVariable[string] `spec:"title=Raw Body,type=string,scope=Message,name=body,messageScope"`
     OutJSON       runtime.OutVariable[any]    `spec:"title=JSON,type=object,scope=Message,name=json,messageScope"`
     OutRow        runtime.OutVariable[any]    `spec:"title=Row,type=object,scope=Message,name=row,messageScope"`
+
+    requests nodeRequests
 }
 
 func (n *SeaTableGetRow) OnCreate() error { return nil }
-func (n *SeaTableGetRow) OnClose() error  { return nil }
+
+func (n *SeaTableGetRow) OnClose() error {
+    n.requests.cancelAll()
+    return nil
+}
 
 func (n *SeaTableGetRow) OnMessage(ctx message.Context) error {
     clientID, err := n.InClientID.Get(ctx)
@@ -62,39 +69,42 @@ func (n *SeaTableGetRow) OnMessage(ctx message.Context) error {
     viewName, _ := n.OptViewName.Get(ctx)
     convert, _ := n.OptConvert.Get(ctx)
 
-    u, err := url.Parse(fmt.Sprintf("%s/api-gateway/api/v2/dtables/%s/rows/%s/", cfg.Server, cfg.BaseUUID, rowID))
-    if err != nil {
-        return fmt.Errorf("parse Get Row URL: %w", err)
-    }
-    q := u.Query()
-    q.Set("table_name", tableName)
-    if strings.TrimSpace(viewName) != "" {
-        q.Set("view_name", viewName)
-    }
-    if convert {
-        q.Set("convert_keys", "true")
+    timeout, _ := n.OptTimeout.Get(ctx)
+    goCtx, done := n.requests.begin(timeout)
+    defer done()
+
+    if tableName, err = resolveTableName(goCtx, cfg, tableName); err != nil {
+        return err
     }
-    u.RawQuery = q.Encode()
 
-    respBody, status, err := doSeaTableRequest(context.Background(), "GET", u.String(), cfg.Token, nil)
-    if err != nil {
+    row, resp, err := cfg.GetRow(goCtx, tableName, rowID, &seatable.GetRowOptions{
+        View:        viewName,
+        ConvertKeys: convert,
+    })
+    failOnError, _ := n.OptFailOnError.Get(ctx)
+    if err := checkSeaTableError(failOnError, err); err != nil {
         return err
     }
 
-    n.OutStatusCode.Set(ctx, status)
-    n.OutRaw.Set(ctx, string(respBody))
+    n.OutStatusCode.Set(ctx, resp.StatusCode)
+    n.OutRaw.Set(ctx, string(resp.Body))
+
+    if typed, _ := n.OptTypedOutput.Get(ctx); typed && row != nil {
+        decoder, err := cfg.TableDecoder(goCtx, tableName)
+        if err != nil {
+            return seaTableError(err)
+        }
+        decoder.Row(row)
+        n.OutJSON.Set(ctx, row)
+        n.OutRow.Set(ctx, row)
+        return nil
+    }
 
     var parsed any
-    if err := json.Unmarshal(respBody, &parsed); err == nil {
+    if err := json.Unmarshal(resp.Body, &parsed); err == nil {
         n.OutJSON.Set(ctx, parsed)
     }
 
-    row := parsed
-    if m, ok := parsed.(map[string]any); ok {
-        if v, ok := m["row"]; ok {
-            row = v
-        }
-    }
     n.OutRow.Set(ctx, row)
     return nil
 }
@@ -1,11 +1,11 @@
 package v1
 
 import (
-    "context"
     "encoding/json"
     "fmt"
     "strings"
 
+    "github.com/example/robomotion-seatable/seatable"
     "github.com/robomotionio/robomotion-go/message"
     "github.com/robomotionio/robomotion-go/runtime"
 )
@@ -23,14 +23,22 @@ type SeaTableLink struct {
 
     OptOtherRowID  runtime.OptVariable[string] `spec:"title=Other Row ID (for add/remove),type=string,scope=Message,name=otherRowId,messageScope,customScope,jsScope"`
     OptOtherRowIDs runtime.OptVariable[string] `spec:"title=Other Row IDs (for update),type=string,scope=Message,name=otherRowIds,messageScope,customScope,jsScope"`
+    OptFailOnError runtime.OptVariable[bool] `spec:"title=Fail on HTTP Error,type=bool,value=false,scope=Message,name=failOnError,messageScope,customScope,jsScope"`
+    OptTimeout runtime.OptVariable[int] `spec:"title=Timeout (seconds),type=int,value=0,scope=Message,name=timeout,messageScope,customScope,jsScope"`
 
     OutStatusCode runtime.OutVariable[int]    `spec:"title=Status Code,type=int,scope=Message,name=statusCode,messageScope"`
     OutRaw        runtime.OutVariable[string] `spec:"title=Raw Body,type=string,scope=Message,name=body,messageScope"`
     OutJSON       runtime.OutVariable[any]    `spec:"title=JSON,type=object,scope=Message,name=json,messageScope"`
+
+    requests nodeRequests
 }
 
 func (n *SeaTableLink) OnCreate() error { return nil }
-func (n *SeaTableLink) OnClose() error  { return nil }
+
+func (n *SeaTableLink) OnClose() error {
+    n.requests.cancelAll()
+    return nil
+}
 
 func (n *SeaTableLink) OnMessage(ctx message.Context) error {
     clientID, err := n.InClientID.Get(ctx)
@@ -73,39 +81,18 @@ func (n *SeaTableLink) OnMessage(ctx message.Context) error {
         return runtime.NewError("ErrInvalidArg", "Link ID, Table, Other Table and Row ID are required")
     }
 
-    url := fmt.Sprintf("%s/api-gateway/api/v2/dtables/%s/links/", cfg.Server, cfg.BaseUUID)
-    var method string
-    var payload map[string]any
+    link := seatable.Link{ID: linkID, Table: tableName, OtherTable: otherTableName}
 
+    var (
+        otherRowID  string
+        otherRowIDs []string
+    )
     switch op {
-    case "add":
-        otherRowID, _ := n.OptOtherRowID.Get(ctx)
+    case "add", "remove":
+        otherRowID, _ = n.OptOtherRowID.Get(ctx)
         otherRowID = strings.TrimSpace(otherRowID)
         if otherRowID == "" {
-            return runtime.NewError("ErrInvalidArg", "Other Row ID is required for add")
-        }
-        method = "POST"
-        payload = map[string]any{
-            "link_id":            linkID,
-            "table_name":         tableName,
-            "other_table_name":   otherTableName,
-            "table_row_id":       rowID,
-            "other_table_row_id": otherRowID,
-        }
-
-    case "remove":
-        otherRowID, _ := n.OptOtherRowID.Get(ctx)
-        otherRowID = strings.TrimSpace(otherRowID)
-        if otherRowID == "" {
-            return runtime.NewError("ErrInvalidArg", "Other Row ID is required for remove")
-        }
-        method = "DELETE"
-        payload = map[string]any{
-            "link_id":            linkID,
-            "table_name":         tableName,
-            "other_table_name":   otherTableName,
-            "table_row_id":       rowID,
-            "other_table_row_id": otherRowID,
+            return runtime.NewError("ErrInvalidArg", "Other Row ID is required for "+op)
         }
 
     case "update":
@@ -118,29 +105,43 @@ func (n *SeaTableLink) OnMessage(ctx message.Context) error {
         if err != nil {
             return runtime.NewError("ErrInvalidArg", fmt.Sprintf("parse Other Row IDs: %v", err))
         }
-        method = "PUT"
-        payload = map[string]any{
-            "link_id":          linkID,
-            "table_name":       tableName,
-            "other_table_name": otherTableName,
-            "row_id":           rowID,
-            "other_rows_ids":   ids,
-        }
+        otherRowIDs = ids
 
     default:
         return runtime.NewError("ErrInvalidArg", "Operation must be add, update or remove")
     }
 
-    respBody, status, err := doSeaTableRequest(context.Background(), method, url, cfg.Token, payload)
-    if err != nil {
+    timeout, _ := n.OptTimeout.Get(ctx)
+    goCtx, done := n.requests.begin(timeout)
+    defer done()
+
+    if link.Table, err = resolveTableName(goCtx, cfg, link.Table); err != nil {
+        return err
+    }
+    if link.OtherTable, err = resolveTableName(goCtx, cfg, link.OtherTable); err != nil {
+        return err
+    }
+
+    var resp *seatable.Response
+    switch op {
+    case "add":
+        resp, err = cfg.AddLink(goCtx, link, rowID, otherRowID)
+    case "remove":
+        resp, err = cfg.RemoveLink(goCtx, link, rowID, otherRowID)
+    case "update":
+        resp, err = cfg.UpdateLinks(goCtx, link, rowID, otherRowIDs)
+    }
+
+    failOnError, _ := n.OptFailOnError.Get(ctx)
+    if err := checkSeaTableError(failOnError, err); err != nil {
         return err
     }
 
-    n.OutStatusCode.Set(ctx, status)
-    n.OutRaw.Set(ctx, string(respBody))
+    n.OutStatusCode.Set(ctx, resp.StatusCode)
+    n.OutRaw.Set(ctx, string(resp.Body))
 
     var parsed any
-    if err := json.Unmarshal(respBody, &parsed); err == nil {
+    if err := json.Unmarshal(resp.Body, &parsed); err == nil {
         n.OutJSON.Set(ctx, parsed)
     }
     return nil
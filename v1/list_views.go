@@ -1,32 +1,41 @@
 package v1
 
 import (
-	"context"
 	"encoding/json"
-	"fmt"
-	"net/url"
 	"strings"
 
+	"github.com/example/robomotion-seatable/seatable"
 	"github.com/robomotionio/robomotion-go/message"
 	"github.com/robomotionio/robomotion-go/runtime"
 )
 
-// SeaTableListViews lists all views of a specific table.
+// SeaTableListViews lists all views of a specific table, read from the
+// client's metadata cache.
 type SeaTableListViews struct {
 	runtime.Node `spec:"id=Robomotion.SeaTable.ListViews,name=List Views,icon=mdiViewList,color=#00C2E0,inputs=1,outputs=1"`
 
 	InClientID  runtime.InVariable[string] `spec:"title=Client ID,type=string,scope=Message,name=clientId,messageScope,jsScope,customScope"`
 	InTableName runtime.InVariable[string] `spec:"title=Table Name,type=string,scope=Message,name=tableName,messageScope,jsScope,customScope"`
 
+	OptRefresh     runtime.OptVariable[bool] `spec:"title=Refresh Metadata,type=bool,value=false,scope=Message,name=refresh,messageScope,customScope,jsScope"`
+	OptFailOnError runtime.OptVariable[bool] `spec:"title=Fail on HTTP Error,type=bool,value=false,scope=Message,name=failOnError,messageScope,customScope,jsScope"`
+	OptTimeout     runtime.OptVariable[int]  `spec:"title=Timeout (seconds),type=int,value=0,scope=Message,name=timeout,messageScope,customScope,jsScope"`
+
 	OutStatusCode runtime.OutVariable[int]    `spec:"title=Status Code,type=int,scope=Message,name=statusCode,messageScope"`
 	OutRaw        runtime.OutVariable[string] `spec:"title=Raw Body,type=string,scope=Message,name=body,messageScope"`
 	OutJSON       runtime.OutVariable[any]    `spec:"title=JSON,type=object,scope=Message,name=json,messageScope"`
 	OutViews      runtime.OutVariable[any]    `spec:"title=Views,type=object,scope=Message,name=views,messageScope"`
 	OutCount      runtime.OutVariable[int]    `spec:"title=Count,type=int,scope=Message,name=count,messageScope"`
+
+	requests nodeRequests
 }
 
 func (n *SeaTableListViews) OnCreate() error { return nil }
-func (n *SeaTableListViews) OnClose() error  { return nil }
+
+func (n *SeaTableListViews) OnClose() error {
+	n.requests.cancelAll()
+	return nil
+}
 
 func (n *SeaTableListViews) OnMessage(ctx message.Context) error {
 	clientID, err := n.InClientID.Get(ctx)
@@ -47,40 +56,36 @@ func (n *SeaTableListViews) OnMessage(ctx message.Context) error {
 		return runtime.NewError("ErrInvalidArg", "Table Name is required")
 	}
 
-	u, err := url.Parse(fmt.Sprintf("%s/api-gateway/api/v2/dtables/%s/views/", cfg.Server, cfg.BaseUUID))
-	if err != nil {
-		return fmt.Errorf("parse URL: %w", err)
-	}
-	q := u.Query()
-	q.Set("table_name", tableName)
-	u.RawQuery = q.Encode()
+	timeout, _ := n.OptTimeout.Get(ctx)
+	goCtx, done := n.requests.begin(timeout)
+	defer done()
 
-	respBody, status, err := doSeaTableRequest(context.Background(), "GET", u.String(), cfg.Token, nil)
-	if err != nil {
-		return err
+	if refresh, _ := n.OptRefresh.Get(ctx); refresh {
+		cfg.InvalidateSchema()
 	}
-
-	n.OutStatusCode.Set(ctx, status)
-	n.OutRaw.Set(ctx, string(respBody))
-
-	var parsed any
-	if err := json.Unmarshal(respBody, &parsed); err == nil {
-		n.OutJSON.Set(ctx, parsed)
+	_, resp, err := cfg.CachedMetadata(goCtx)
+	failOnError, _ := n.OptFailOnError.Get(ctx)
+	if err := checkSeaTableError(failOnError, err); err != nil {
+		return err
 	}
+	n.OutStatusCode.Set(ctx, resp.StatusCode)
 
-	// Extract views array
-	var views []any
-	if m, ok := parsed.(map[string]any); ok {
-		if v, ok := m["views"].([]any); ok {
-			views = v
+	views := []seatable.View{}
+	if err == nil {
+		table, err := cfg.ResolveTable(goCtx, tableName)
+		if err != nil {
+			return seaTableError(err)
+		}
+		if table.Views != nil {
+			views = table.Views
 		}
 	}
-	if views == nil {
-		views = []any{}
-	}
+
+	body, _ := json.Marshal(map[string]any{"views": views})
+	n.OutRaw.Set(ctx, string(body))
+	n.OutJSON.Set(ctx, map[string]any{"views": views})
 	n.OutViews.Set(ctx, views)
 	n.OutCount.Set(ctx, len(views))
 
 	return nil
 }
-
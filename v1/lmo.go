@@ -0,0 +1,84 @@
+package v1
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+
+	"github.com/robomotionio/robomotion-go/message"
+	"github.com/robomotionio/robomotion-go/runtime"
+	"github.com/robomotionio/robomotion-go/utils"
+)
+
+// lmoThreshold is the encoded size above which row-heavy outputs are stored
+// as a LargeMessageObject instead of being put into the message. The SDK only
+// does this above runtime.LMO_LIMIT, which is too late for big row sets.
+const lmoThreshold = 64 << 10
+
+// setLarge sets a Message scoped output, storing value as a
+// LargeMessageObject when its JSON encoding is at least lmoThreshold bytes.
+// Downstream nodes read it back transparently through their InVariable.
+// Other scopes, small values and robots without LMO support use out.Set.
+func setLarge[T any](ctx message.Context, out *runtime.OutVariable[T], value T) error {
+	_, err := storeLarge(ctx, out, value)
+	return err
+}
+
+// storeLarge is setLarge reporting whether value was stored as a
+// LargeMessageObject, so outputs repeating it can be left out.
+func storeLarge[T any](ctx message.Context, out *runtime.OutVariable[T], value T) (bool, error) {
+	name, ok := out.Name.(string)
+	if out.Scope != "Message" || !ok || name == "" || !runtime.IsLMOCapable() {
+		return false, out.Set(ctx, value)
+	}
+	lmo, err := newLMO(value)
+	if err != nil {
+		return false, err
+	}
+	if lmo == nil {
+		return false, out.Set(ctx, value)
+	}
+	return true, ctx.Set(name, lmo)
+}
+
+// newLMO writes value to a LargeMessageObject file when its JSON encoding is
+// at least lmoThreshold bytes and returns the reference to put into the
+// message. Smaller values return nil.
+func newLMO(value any) (*runtime.LargeMessageObject, error) {
+	data, err := json.Marshal(value)
+	if err != nil || len(data) < lmoThreshold {
+		return nil, nil
+	}
+
+	lmo := &runtime.LargeMessageObject{
+		Magic:   runtime.LMO_MAGIC,
+		Version: runtime.LMO_VERSION,
+		ID:      runtime.NewId(),
+		Head:    string(data[:runtime.LMO_HEAD]),
+		Size:    int64(len(data)),
+		Data:    json.RawMessage(data),
+	}
+	if err := writeLMO(lmo); err != nil {
+		return nil, err
+	}
+	lmo.Data = nil
+	return lmo, nil
+}
+
+// writeLMO stores lmo where runtime.DeserializeLMO looks for it. The SDK
+// does not export its writer for a custom threshold, so this mirrors the
+// layout of SerializeLMO and DeserializeLMO in robomotion-go v1.7.0:
+// <temp>/robots/<robot id>/<id>.lmo holding the JSON encoded object.
+// Re-check it, and lmo_test.go, when upgrading the SDK.
+func writeLMO(lmo *runtime.LargeMessageObject) error {
+	robotID, _ := runtime.GetRobotID()
+	dir := path.Join(utils.GetTempPath(), "robots", robotID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	b, err := json.Marshal(lmo)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path.Join(dir, lmo.ID+".lmo"), b, 0644)
+}
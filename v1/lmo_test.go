@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/robomotionio/robomotion-go/runtime"
+)
+
+func TestLMORoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	rows := make([]any, 0, 2000)
+	for i := 0; i < cap(rows); i++ {
+		rows = append(rows, map[string]any{"_id": "row", "Name": strings.Repeat("x", 40), "Count": float64(i)})
+	}
+	lmo, err := newLMO(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lmo == nil {
+		t.Fatal("large value was not stored")
+	}
+	if lmo.Data != nil {
+		t.Error("reference still carries the data")
+	}
+
+	got, err := runtime.DeserializeLMO(lmo.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Magic != runtime.LMO_MAGIC || got.Version != runtime.LMO_VERSION || got.Size != lmo.Size {
+		t.Errorf("header = %d/%d/%d, want %d/%d/%d", got.Magic, got.Version, got.Size, runtime.LMO_MAGIC, runtime.LMO_VERSION, lmo.Size)
+	}
+	if !reflect.DeepEqual(got.Data, rows) {
+		t.Error("data changed in the round trip")
+	}
+
+	small, err := newLMO(rows[:1])
+	if err != nil || small != nil {
+		t.Errorf("small value: lmo = %v, err = %v, want nil", small, err)
+	}
+}
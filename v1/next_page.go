@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"strings"
+
+	"github.com/example/robomotion-seatable/seatable"
+	"github.com/robomotionio/robomotion-go/message"
+	"github.com/robomotionio/robomotion-go/runtime"
+)
+
+// SeaTableNextPage reads the next page of a cursor opened by Open Cursor. The
+// cursor is released once Has More is false.
+type SeaTableNextPage struct {
+	runtime.Node `spec:"id=Robomotion.SeaTable.NextPage,name=Next Page,icon=mdiPageNext,color=#00C2E0,inputs=1,outputs=1"`
+
+	InClientID runtime.InVariable[string] `spec:"title=Client ID,type=string,scope=Message,name=clientId,messageScope,jsScope,customScope"`
+	InCursorID runtime.InVariable[string] `spec:"title=Cursor ID,type=string,scope=Message,name=cursorId,messageScope,jsScope,customScope"`
+
+	OptPageSize    runtime.OptVariable[int]  `spec:"title=Page Size,type=int,value=0,scope=Message,name=pageSize,messageScope,customScope,jsScope"`
+	OptFailOnError runtime.OptVariable[bool] `spec:"title=Fail on HTTP Error,type=bool,value=false,scope=Message,name=failOnError,messageScope,customScope,jsScope"`
+	OptTimeout     runtime.OptVariable[int]  `spec:"title=Timeout (seconds),type=int,value=0,scope=Message,name=timeout,messageScope,customScope,jsScope"`
+
+	OutStatusCode runtime.OutVariable[int]  `spec:"title=Status Code,type=int,scope=Message,name=statusCode,messageScope"`
+	OutRows       runtime.OutVariable[any]  `spec:"title=Rows,type=object,scope=Message,name=rows,messageScope"`
+	OutCount      runtime.OutVariable[int]  `spec:"title=Count,type=int,scope=Message,name=count,messageScope"`
+	OutHasMore    runtime.OutVariable[bool] `spec:"title=Has More,type=bool,scope=Message,name=hasMore,messageScope"`
+	OutOffset     runtime.OutVariable[int]  `spec:"title=Offset,type=int,scope=Message,name=offset,messageScope"`
+
+	requests nodeRequests
+}
+
+func (n *SeaTableNextPage) OnCreate() error { return nil }
+
+func (n *SeaTableNextPage) OnClose() error {
+	n.requests.cancelAll()
+	return nil
+}
+
+func (n *SeaTableNextPage) OnMessage(ctx message.Context) error {
+	clientID, err := n.InClientID.Get(ctx)
+	if err != nil {
+		return err
+	}
+	cfg, ok := getSeaTableClient(clientID)
+	if !ok {
+		return runtime.NewError("ErrInvalidArg", "Unknown Client ID – run SeaTable.Connect first")
+	}
+
+	cursorID, err := n.InCursorID.Get(ctx)
+	if err != nil {
+		return err
+	}
+	cursorID = strings.TrimSpace(cursorID)
+	cur, ok := cfg.cursor(cursorID)
+	if !ok {
+		return runtime.NewError("ErrInvalidArg", "Unknown Cursor ID – run SeaTable.OpenCursor first")
+	}
+
+	pageSize, _ := n.OptPageSize.Get(ctx)
+
+	timeout, _ := n.OptTimeout.Get(ctx)
+	goCtx, done := n.requests.begin(timeout)
+	defer done()
+
+	rows, hasMore, resp, err := cur.Next(goCtx, pageSize)
+	failOnError, _ := n.OptFailOnError.Get(ctx)
+	if err := checkSeaTableError(failOnError, err); err != nil {
+		return err
+	}
+	if err != nil {
+		rows = []seatable.Row{}
+	} else if !hasMore {
+		cfg.removeCursor(cursorID)
+	}
+
+	n.OutStatusCode.Set(ctx, resp.StatusCode)
+	n.OutRows.Set(ctx, rows)
+	n.OutCount.Set(ctx, len(rows))
+	n.OutHasMore.Set(ctx, hasMore)
+	n.OutOffset.Set(ctx, cur.Offset())
+	return nil
+}
@@ -0,0 +1,80 @@
+package v1
+
+import (
+	"strings"
+
+	"github.com/example/robomotion-seatable/seatable"
+	"github.com/robomotionio/robomotion-go/message"
+	"github.com/robomotionio/robomotion-go/runtime"
+)
+
+// SeaTableOpenCursor opens a row cursor on a table. Use Next Page with the
+// returned Cursor ID to read the rows page by page.
+type SeaTableOpenCursor struct {
+	runtime.Node `spec:"id=Robomotion.SeaTable.OpenCursor,name=Open Cursor,icon=mdiCursorDefaultOutline,color=#00C2E0,inputs=1,outputs=1"`
+
+	InClientID  runtime.InVariable[string] `spec:"title=Client ID,type=string,scope=Message,name=clientId,messageScope,jsScope,customScope"`
+	InTableName runtime.InVariable[string] `spec:"title=Table Name,type=string,scope=Message,name=tableName,messageScope,jsScope,customScope"`
+
+	OptViewName runtime.OptVariable[string] `spec:"title=View Name,type=string,scope=Message,name=viewName,messageScope,customScope,jsScope"`
+	OptFilter   runtime.OptVariable[any]    `spec:"title=Filter,type=object,scope=Message,name=filter,messageScope,customScope,jsScope"`
+	OptStart    runtime.OptVariable[int]    `spec:"title=Start Offset,type=int,value=0,scope=Message,name=start,messageScope,customScope,jsScope"`
+	OptPageSize runtime.OptVariable[int]    `spec:"title=Page Size,type=int,value=1000,scope=Message,name=pageSize,messageScope,customScope,jsScope"`
+	OptConvert  runtime.OptVariable[bool]   `spec:"title=Convert Keys,type=bool,value=true,scope=Message,name=convertKeys,messageScope,customScope,jsScope"`
+	OptTimeout  runtime.OptVariable[int]    `spec:"title=Timeout (seconds),type=int,value=0,scope=Message,name=timeout,messageScope,customScope,jsScope"`
+
+	OutCursorID runtime.OutVariable[string] `spec:"title=Cursor ID,type=string,scope=Message,name=cursorId,messageScope"`
+
+	requests nodeRequests
+}
+
+func (n *SeaTableOpenCursor) OnCreate() error { return nil }
+
+func (n *SeaTableOpenCursor) OnClose() error {
+	n.requests.cancelAll()
+	return nil
+}
+
+func (n *SeaTableOpenCursor) OnMessage(ctx message.Context) error {
+	clientID, err := n.InClientID.Get(ctx)
+	if err != nil {
+		return err
+	}
+	cfg, ok := getSeaTableClient(clientID)
+	if !ok {
+		return runtime.NewError("ErrInvalidArg", "Unknown Client ID – run SeaTable.Connect first")
+	}
+
+	tableName, err := n.InTableName.Get(ctx)
+	if err != nil {
+		return err
+	}
+	tableName = strings.TrimSpace(tableName)
+	if tableName == "" {
+		return runtime.NewError("ErrInvalidArg", "Table Name is required")
+	}
+
+	opts := &seatable.CursorOptions{}
+	opts.View, _ = n.OptViewName.Get(ctx)
+	opts.Start, _ = n.OptStart.Get(ctx)
+	opts.PageSize, _ = n.OptPageSize.Get(ctx)
+	opts.ConvertKeys, _ = n.OptConvert.Get(ctx)
+	filterValue, _ := n.OptFilter.Get(ctx)
+	if opts.Filter, err = parseFilter(filterValue); err != nil {
+		return err
+	}
+	if opts.Filter != nil && strings.TrimSpace(opts.View) != "" {
+		return runtime.NewError("ErrInvalidArg", "View Name cannot be combined with Filter")
+	}
+
+	timeout, _ := n.OptTimeout.Get(ctx)
+	goCtx, done := n.requests.begin(timeout)
+	defer done()
+
+	if tableName, err = resolveTableName(goCtx, cfg, tableName); err != nil {
+		return err
+	}
+
+	cursorID := cfg.addCursor(cfg.OpenCursor(tableName, opts))
+	return n.OutCursorID.Set(ctx, cursorID)
+}
@@ -3,37 +3,59 @@ package v1
 import (
     "context"
     "encoding/json"
+    "errors"
     "fmt"
-    "net/url"
-    "strconv"
+    "sort"
     "strings"
 
+    "github.com/example/robomotion-seatable/seatable"
     "github.com/robomotionio/robomotion-go/message"
     "github.com/robomotionio/robomotion-go/runtime"
 )
 
-// SeaTableRows provides list / append / update / delete for rows.
+// SeaTableRows provides list / append / update / delete for rows, with batch
+// variants for many rows at once.
 type SeaTableRows struct {
     runtime.Node `spec:"id=Robomotion.SeaTable.Rows,name=Rows,icon=mdiTable,color=#00C2E0,inputs=1,outputs=1"`
 
     InClientID runtime.InVariable[string] `spec:"title=Client ID,type=string,scope=Message,name=clientId,messageScope,jsScope,customScope"`
-    OptAction  string                     `spec:"title=Action,value=list,enum=list|append|update|delete,enumNames=List|Append|Update|Delete,option"`
+    OptAction  string                     `spec:"title=Action,value=list,enum=list|append|appendMany|update|updateMany|delete|deleteMany,enumNames=List|Append|Append Many|Update|Update Many|Delete|Delete Many,option"`
 
     InTableName runtime.InVariable[string]      `spec:"title=Table Name,type=string,scope=Message,name=tableName,messageScope,jsScope,customScope"`
     OptViewName runtime.OptVariable[string]     `spec:"title=View Name,type=string,scope=Message,name=viewName,messageScope,customScope,jsScope"`
+    OptFilter   runtime.OptVariable[any]        `spec:"title=Filter,type=object,scope=Message,name=filter,messageScope,customScope,jsScope"`
     OptStart    runtime.OptVariable[int]        `spec:"title=Start,type=int,value=0,scope=Message,name=start,messageScope,customScope,jsScope"`
     OptLimit    runtime.OptVariable[int]        `spec:"title=Limit,type=int,value=1000,scope=Message,name=limit,messageScope,customScope,jsScope"`
     OptConvert  runtime.OptVariable[bool]       `spec:"title=Convert Keys,type=bool,value=true,scope=Message,name=convertKeys,messageScope,customScope,jsScope"`
     OptRowID    runtime.OptVariable[string]     `spec:"title=Row ID,type=string,scope=Message,name=rowId,messageScope,customScope,jsScope"`
     OptRowData  runtime.OptVariable[any]        `spec:"title=Row Data,type=object,scope=Message,name=rowData,messageScope,customScope,jsScope"`
+    OptRows     runtime.OptVariable[any]        `spec:"title=Rows,type=object,scope=Message,name=rows,messageScope,customScope,jsScope"`
+    OptAnchorRowID runtime.OptVariable[string] `spec:"title=Anchor Row ID,type=string,scope=Message,name=anchorRowId,messageScope,customScope,jsScope"`
+    OptInsertPosition string `spec:"title=Insert Position,value=below,enum=below|above,enumNames=Below Anchor|Above Anchor,option"`
+    OptRowIDs   runtime.OptVariable[any]        `spec:"title=Row IDs,type=object,scope=Message,name=rowIds,messageScope,customScope,jsScope"`
+    OptChunkSize   runtime.OptVariable[int] `spec:"title=Chunk Size,type=int,value=1000,scope=Message,name=chunkSize,messageScope,customScope,jsScope"`
+    OptParallelism runtime.OptVariable[int] `spec:"title=Parallelism,type=int,value=1,scope=Message,name=parallelism,messageScope,customScope,jsScope"`
+    OptContinueOnError runtime.OptVariable[bool] `spec:"title=Continue on Chunk Error,type=bool,value=true,scope=Message,name=continueOnError,messageScope,customScope,jsScope"`
+    OptCoerce      runtime.OptVariable[bool]   `spec:"title=Coerce Values,type=bool,value=false,scope=Message,name=coerceValues,messageScope,customScope,jsScope"`
+    OptDateFormats runtime.OptVariable[string] `spec:"title=Date Formats (; separated),type=string,scope=Message,name=dateFormats,messageScope,customScope,jsScope"`
+    OptDecimalSeparator string `spec:"title=Decimal Separator,value=dot,enum=dot|comma,enumNames=Dot|Comma,option"`
+    OptTypedOutput runtime.OptVariable[bool] `spec:"title=Typed Output,type=bool,value=false,scope=Message,name=typedOutput,messageScope,customScope,jsScope"`
+    OptFailOnError runtime.OptVariable[bool] `spec:"title=Fail on HTTP Error,type=bool,value=false,scope=Message,name=failOnError,messageScope,customScope,jsScope"`
+    OptTimeout runtime.OptVariable[int] `spec:"title=Timeout (seconds),type=int,value=0,scope=Message,name=timeout,messageScope,customScope,jsScope"`
 
     OutStatusCode runtime.OutVariable[int]         `spec:"title=Status Code,type=int,scope=Message,name=statusCode,messageScope"`
     OutRaw        runtime.OutVariable[string]      `spec:"title=Raw Body,type=string,scope=Message,name=body,messageScope"`
     OutJSON       runtime.OutVariable[any]         `spec:"title=JSON,type=object,scope=Message,name=json,messageScope"`
+
+    requests nodeRequests
 }
 
 func (n *SeaTableRows) OnCreate() error { return nil }
-func (n *SeaTableRows) OnClose() error  { return nil }
+
+func (n *SeaTableRows) OnClose() error {
+    n.requests.cancelAll()
+    return nil
+}
 
 func (n *SeaTableRows) OnMessage(ctx message.Context) error {
     clientID, err := n.InClientID.Get(ctx)
@@ -59,95 +81,314 @@ func (n *SeaTableRows) OnMessage(ctx message.Context) error {
         action = "list"
     }
 
+    timeout, _ := n.OptTimeout.Get(ctx)
+    goCtx, done := n.requests.begin(timeout)
+    defer done()
+
+    if tableName, err = resolveTableName(goCtx, cfg, tableName); err != nil {
+        return err
+    }
+
     var (
-        method   = "GET"
-        urlStr   string
-        bodyData any
+        resp      *seatable.Response
+        typedRows []seatable.Row
     )
 
     switch action {
     case "list":
-        u, err := url.Parse(fmt.Sprintf("%s/api-gateway/api/v2/dtables/%s/rows/", cfg.Server, cfg.BaseUUID))
-        if err != nil {
-            return err
-        }
-        q := u.Query()
-        q.Set("table_name", tableName)
-
-        if v, err := n.OptViewName.Get(ctx); err == nil && strings.TrimSpace(v) != "" {
-            q.Set("view_name", v)
-        }
-        if s, err := n.OptStart.Get(ctx); err == nil && s > 0 {
-            q.Set("start", strconv.Itoa(s))
+        opts := &seatable.ListRowsOptions{}
+        opts.View, _ = n.OptViewName.Get(ctx)
+        opts.Start, _ = n.OptStart.Get(ctx)
+        opts.Limit, _ = n.OptLimit.Get(ctx)
+        opts.ConvertKeys, _ = n.OptConvert.Get(ctx)
+        filterValue, _ := n.OptFilter.Get(ctx)
+        filter, filterErr := parseFilter(filterValue)
+        if filterErr != nil {
+            return filterErr
         }
-        if l, err := n.OptLimit.Get(ctx); err == nil && l > 0 {
-            q.Set("limit", strconv.Itoa(l))
+        var rows []seatable.Row
+        if filter != nil {
+            if strings.TrimSpace(opts.View) != "" {
+                return runtime.NewError("ErrInvalidArg", "View Name cannot be combined with Filter")
+            }
+            rows, resp, err = cfg.QueryRows(goCtx, tableName, filter, &seatable.QueryRowsOptions{
+                Start:       opts.Start,
+                Limit:       opts.Limit,
+                ConvertKeys: opts.ConvertKeys,
+            })
+        } else {
+            rows, resp, err = cfg.ListRows(goCtx, tableName, opts)
         }
-        if c, err := n.OptConvert.Get(ctx); err == nil && c {
-            q.Set("convert_keys", "true")
+        if typed, _ := n.OptTypedOutput.Get(ctx); typed && err == nil {
+            decoder, decErr := cfg.TableDecoder(goCtx, tableName)
+            if decErr != nil {
+                return seaTableError(decErr)
+            }
+            typedRows = decoder.Rows(rows)
         }
-        u.RawQuery = q.Encode()
-        urlStr = u.String()
-        method = "GET"
-        bodyData = nil
 
     case "append":
-        method = "POST"
-        rowData, err := n.OptRowData.Get(ctx)
-        if err != nil || rowData == nil {
+        row, rowErr := n.rowData(ctx)
+        if rowErr != nil {
             return runtime.NewError("ErrInvalidArg", "Row Data is required for append")
         }
-        urlStr = fmt.Sprintf("%s/api-gateway/api/v2/dtables/%s/rows/", cfg.Server, cfg.BaseUUID)
-        bodyData = map[string]any{
-            "table_name": tableName,
-            "row":        rowData,
+        if row, rowErr = n.coerce(goCtx, ctx, cfg, tableName, row, "Row Data"); rowErr != nil {
+            return rowErr
+        }
+        if anchor := n.anchorRowID(ctx); anchor != "" {
+            _, resp, err = cfg.InsertRow(goCtx, tableName, row, anchor, n.insertPosition())
+        } else {
+            resp, err = cfg.AppendRow(goCtx, tableName, row)
         }
 
+    case "appendMany", "updateMany", "deleteMany":
+        return n.batch(goCtx, ctx, cfg, tableName, action)
+
     case "update":
-        method = "PUT"
-        rowID, err := n.OptRowID.Get(ctx)
-        if err != nil || strings.TrimSpace(rowID) == "" {
+        rowID, _ := n.OptRowID.Get(ctx)
+        if strings.TrimSpace(rowID) == "" {
             return runtime.NewError("ErrInvalidArg", "Row ID is required for update")
         }
-        rowData, err := n.OptRowData.Get(ctx)
-        if err != nil || rowData == nil {
+        row, rowErr := n.rowData(ctx)
+        if rowErr != nil {
             return runtime.NewError("ErrInvalidArg", "Row Data is required for update")
         }
-        urlStr = fmt.Sprintf("%s/api-gateway/api/v2/dtables/%s/rows/", cfg.Server, cfg.BaseUUID)
-        bodyData = map[string]any{
-            "table_name": tableName,
-            "row_id":     rowID,
-            "row":        rowData,
+        if row, rowErr = n.coerce(goCtx, ctx, cfg, tableName, row, "Row Data"); rowErr != nil {
+            return rowErr
         }
+        resp, err = cfg.UpdateRow(goCtx, tableName, rowID, row)
 
     case "delete":
-        method = "DELETE"
-        rowID, err := n.OptRowID.Get(ctx)
-        if err != nil || strings.TrimSpace(rowID) == "" {
+        rowID, _ := n.OptRowID.Get(ctx)
+        if strings.TrimSpace(rowID) == "" {
             return runtime.NewError("ErrInvalidArg", "Row ID is required for delete")
         }
-        urlStr = fmt.Sprintf("%s/api-gateway/api/v2/dtables/%s/rows/", cfg.Server, cfg.BaseUUID)
-        bodyData = map[string]any{
-            "table_name": tableName,
-            "row_id":     rowID,
-        }
+        resp, err = cfg.DeleteRow(goCtx, tableName, rowID)
 
     default:
         return runtime.NewError("ErrInvalidArg", "Unsupported action for Rows")
     }
 
-    respBody, status, err := doSeaTableRequest(context.Background(), method, urlStr, cfg.Token, bodyData)
-    if err != nil {
+    failOnError, _ := n.OptFailOnError.Get(ctx)
+    if err := checkSeaTableError(failOnError, err); err != nil {
         return err
     }
 
-    n.OutStatusCode.Set(ctx, status)
-    n.OutRaw.Set(ctx, string(respBody))
+    n.OutStatusCode.Set(ctx, resp.StatusCode)
+    n.OutRaw.Set(ctx, string(resp.Body))
+
+    if typedRows != nil {
+        n.OutJSON.Set(ctx, map[string]any{"rows": typedRows})
+        return nil
+    }
 
     var parsed any
-    if err := json.Unmarshal(respBody, &parsed); err == nil {
+    if err := json.Unmarshal(resp.Body, &parsed); err == nil {
         n.OutJSON.Set(ctx, parsed)
     }
 
     return nil
 }
+
+// rowData reads Row Data as a row object.
+func (n *SeaTableRows) rowData(ctx message.Context) (seatable.Row, error) {
+    v, err := n.OptRowData.Get(ctx)
+    if err != nil {
+        return nil, err
+    }
+    if v == nil {
+        return nil, fmt.Errorf("row data is empty")
+    }
+    if m, ok := v.(map[string]any); ok {
+        return seatable.Row(m), nil
+    }
+    b, err := json.Marshal(v)
+    if err != nil {
+        return nil, err
+    }
+    var row seatable.Row
+    if err := json.Unmarshal(b, &row); err != nil {
+        return nil, err
+    }
+    return row, nil
+}
+
+// batch runs one of the batch actions in chunks and reports which rows
+// succeeded and which failed.
+func (n *SeaTableRows) batch(goCtx context.Context, ctx message.Context, cfg *SeaTableClient, tableName, action string) error {
+    opts := &seatable.BatchOptions{}
+    opts.ChunkSize, _ = n.OptChunkSize.Get(ctx)
+    opts.Parallelism, _ = n.OptParallelism.Get(ctx)
+    if cont, err := n.OptContinueOnError.Get(ctx); err == nil {
+        opts.StopOnError = !cont
+    }
+
+    var (
+        result *seatable.BatchResult
+        total  int
+        err    error
+    )
+    switch action {
+    case "appendMany":
+        rows, rowsErr := n.rowsData(ctx)
+        if rowsErr != nil || len(rows) == 0 {
+            return runtime.NewError("ErrInvalidArg", "Rows must be a non-empty array of row objects for appendMany")
+        }
+        for i, row := range rows {
+            if rows[i], err = n.coerce(goCtx, ctx, cfg, tableName, row, fmt.Sprintf("Row %d", i)); err != nil {
+                return err
+            }
+        }
+        total = len(rows)
+        if anchor := n.anchorRowID(ctx); anchor != "" {
+            result, err = cfg.InsertRows(goCtx, tableName, rows, anchor, n.insertPosition(), opts)
+        } else {
+            result, err = cfg.AppendRowsChunked(goCtx, tableName, rows, opts)
+        }
+
+    case "updateMany":
+        updates, updErr := n.updatesData(ctx)
+        if updErr != nil || len(updates) == 0 {
+            return runtime.NewError("ErrInvalidArg", "Rows must be a non-empty array of {row_id, row} objects for updateMany")
+        }
+        for i, u := range updates {
+            if strings.TrimSpace(u.RowID) == "" {
+                return runtime.NewError("ErrInvalidArg", fmt.Sprintf("Row %d has no row_id", i))
+            }
+            if updates[i].Row, err = n.coerce(goCtx, ctx, cfg, tableName, u.Row, fmt.Sprintf("Row %d", i)); err != nil {
+                return err
+            }
+        }
+        total = len(updates)
+        result, err = cfg.UpdateRowsChunked(goCtx, tableName, updates, opts)
+
+    case "deleteMany":
+        ids, idsErr := n.rowIDs(ctx)
+        if idsErr != nil || len(ids) == 0 {
+            return runtime.NewError("ErrInvalidArg", "Row IDs must be a non-empty list for deleteMany")
+        }
+        total = len(ids)
+        result, err = cfg.DeleteRowsChunked(goCtx, tableName, ids, opts)
+    }
+    if err != nil {
+        return seaTableError(err)
+    }
+
+    status := 200
+    if failed := result.FirstError(); failed != nil {
+        status = failed.StatusCode
+        if failOnError, _ := n.OptFailOnError.Get(ctx); failOnError {
+            return runtime.NewError(seaTableErrorCode(status), fmt.Sprintf("%d of %d rows failed: %s", result.Failed, total, failed.Error))
+        }
+    }
+
+    body, _ := json.Marshal(result)
+    n.OutStatusCode.Set(ctx, status)
+    n.OutRaw.Set(ctx, string(body))
+    n.OutJSON.Set(ctx, result)
+    return nil
+}
+
+// anchorRowID returns the trimmed Anchor Row ID, empty when rows go to the end
+// of the table.
+func (n *SeaTableRows) anchorRowID(ctx message.Context) string {
+    anchor, _ := n.OptAnchorRowID.Get(ctx)
+    return strings.TrimSpace(anchor)
+}
+
+func (n *SeaTableRows) insertPosition() string {
+    if n.OptInsertPosition == "above" {
+        return seatable.InsertAbove
+    }
+    return seatable.InsertBelow
+}
+
+// updatesData reads Rows as an array of {row_id, row} objects.
+func (n *SeaTableRows) updatesData(ctx message.Context) ([]seatable.RowUpdate, error) {
+    v, err := n.OptRows.Get(ctx)
+    if err != nil {
+        return nil, err
+    }
+    b, err := json.Marshal(v)
+    if err != nil {
+        return nil, err
+    }
+    var updates []seatable.RowUpdate
+    if err := json.Unmarshal(b, &updates); err != nil {
+        return nil, err
+    }
+    return updates, nil
+}
+
+// rowIDs reads Row IDs as a list of ids. A comma separated string is accepted
+// as well.
+func (n *SeaTableRows) rowIDs(ctx message.Context) ([]string, error) {
+    v, err := n.OptRowIDs.Get(ctx)
+    if err != nil {
+        return nil, err
+    }
+    if s, ok := v.(string); ok {
+        return splitColumns(s), nil
+    }
+    b, err := json.Marshal(v)
+    if err != nil {
+        return nil, err
+    }
+    var ids []string
+    if err := json.Unmarshal(b, &ids); err != nil {
+        return nil, err
+    }
+    return ids, nil
+}
+
+// rowsData reads Rows as an array of row objects.
+func (n *SeaTableRows) rowsData(ctx message.Context) ([]seatable.Row, error) {
+    v, err := n.OptRows.Get(ctx)
+    if err != nil {
+        return nil, err
+    }
+    b, err := json.Marshal(v)
+    if err != nil {
+        return nil, err
+    }
+    var rows []seatable.Row
+    if err := json.Unmarshal(b, &rows); err != nil {
+        return nil, err
+    }
+    return rows, nil
+}
+
+// coerce converts the row values to their column types when Coerce Values is
+// set. Every field that cannot be converted is reported in the error, prefixed
+// with label. Without coercion the column names are still checked against the
+// schema.
+func (n *SeaTableRows) coerce(goCtx context.Context, ctx message.Context, cfg *SeaTableClient, tableName string, row seatable.Row, label string) (seatable.Row, error) {
+    if enabled, _ := n.OptCoerce.Get(ctx); !enabled {
+        names := make([]string, 0, len(row))
+        for k := range row {
+            names = append(names, k)
+        }
+        sort.Strings(names)
+        return row, validateColumnNames(goCtx, cfg, tableName, names)
+    }
+
+    opts := &seatable.CoerceOptions{DecimalComma: n.OptDecimalSeparator == "comma"}
+    if formats, _ := n.OptDateFormats.Get(ctx); strings.TrimSpace(formats) != "" {
+        opts.DateFormats = strings.Split(formats, ";")
+    }
+
+    coercer, err := cfg.TableCoercer(goCtx, tableName, opts)
+    if err != nil {
+        return nil, seaTableError(err)
+    }
+    coerced, err := coercer.Row(row)
+    var coerceErr *seatable.CoerceError
+    if errors.As(err, &coerceErr) {
+        msgs := make([]string, len(coerceErr.Fields))
+        for i, f := range coerceErr.Fields {
+            msgs[i] = f.Error()
+        }
+        return nil, runtime.NewError("ErrValidation", label+" has invalid values: "+strings.Join(msgs, "; "))
+    }
+    return coerced, err
+}
@@ -1,18 +1,16 @@
 package v1
 
 import (
-    "context"
-    "encoding/json"
-    "fmt"
-    "net/url"
-    "strconv"
     "strings"
 
+    "github.com/example/robomotion-seatable/seatable"
     "github.com/robomotionio/robomotion-go/message"
     "github.com/robomotionio/robomotion-go/runtime"
 )
 
-// SeaTableRowsGetMany paginates List Rows to collect many rows.
+// SeaTableRowsGetMany paginates List Rows to collect many rows. With a Filter
+// the pages are read through a parameterized SQL query instead. Concurrency
+// above one fetches several pages at a time.
 type SeaTableRowsGetMany struct {
     runtime.Node `spec:"id=Robomotion.SeaTable.RowsGetMany,name=Rows Get Many,icon=mdiTableMultiple,color=#00C2E0,inputs=1,outputs=1"`
 
@@ -20,18 +18,28 @@ type SeaTableRowsGetMany struct {
     InTableName runtime.InVariable[string] `spec:"title=Table Name,type=string,scope=Message,name=tableName,messageScope,jsScope,customScope"`
 
     OptViewName   runtime.OptVariable[string] `spec:"title=View Name,type=string,scope=Message,name=viewName,messageScope,customScope,jsScope"`
+    OptFilter     runtime.OptVariable[any]    `spec:"title=Filter,type=object,scope=Message,name=filter,messageScope,customScope,jsScope"`
     OptStart      runtime.OptVariable[int]    `spec:"title=Start Offset,type=int,value=0,scope=Message,name=start,messageScope,customScope,jsScope"`
     OptPageSize   runtime.OptVariable[int]    `spec:"title=Page Size,type=int,value=1000,scope=Message,name=pageSize,messageScope,customScope,jsScope"`
     OptMaxRows    runtime.OptVariable[int]    `spec:"title=Max Rows,type=int,value=10000,scope=Message,name=maxRows,messageScope,customScope,jsScope"`
+    OptConcurrency runtime.OptVariable[int]   `spec:"title=Concurrency,type=int,value=1,scope=Message,name=concurrency,messageScope,customScope,jsScope"`
     OptConvert    runtime.OptVariable[bool]   `spec:"title=Convert Keys,type=bool,value=true,scope=Message,name=convertKeys,messageScope,customScope,jsScope"`
+    OptFailOnError runtime.OptVariable[bool]  `spec:"title=Fail on HTTP Error,type=bool,value=false,scope=Message,name=failOnError,messageScope,customScope,jsScope"`
+    OptTimeout runtime.OptVariable[int] `spec:"title=Timeout (seconds),type=int,value=0,scope=Message,name=timeout,messageScope,customScope,jsScope"`
 
     OutStatusCode runtime.OutVariable[int]    `spec:"title=Status Code,type=int,scope=Message,name=statusCode,messageScope"`
     OutRows       runtime.OutVariable[any]    `spec:"title=Rows,type=object,scope=Message,name=rows,messageScope"`
     OutJSON       runtime.OutVariable[any]    `spec:"title=JSON,type=object,scope=Message,name=json,messageScope"`
+
+    requests nodeRequests
 }
 
 func (n *SeaTableRowsGetMany) OnCreate() error { return nil }
-func (n *SeaTableRowsGetMany) OnClose() error  { return nil }
+
+func (n *SeaTableRowsGetMany) OnClose() error {
+    n.requests.cancelAll()
+    return nil
+}
 
 func (n *SeaTableRowsGetMany) OnMessage(ctx message.Context) error {
     clientID, err := n.InClientID.Get(ctx)
@@ -66,84 +74,51 @@ func (n *SeaTableRowsGetMany) OnMessage(ctx message.Context) error {
     }
     convert, _ := n.OptConvert.Get(ctx)
     viewName, _ := n.OptViewName.Get(ctx)
+    failOnError, _ := n.OptFailOnError.Get(ctx)
+    filterValue, _ := n.OptFilter.Get(ctx)
+    filter, err := parseFilter(filterValue)
+    if err != nil {
+        return err
+    }
+    if filter != nil && strings.TrimSpace(viewName) != "" {
+        return runtime.NewError("ErrInvalidArg", "View Name cannot be combined with Filter")
+    }
+
+    concurrency, _ := n.OptConcurrency.Get(ctx)
+
+    timeout, _ := n.OptTimeout.Get(ctx)
+    goCtx, done := n.requests.begin(timeout)
+    defer done()
 
-    allRows := make([]any, 0, pageSize)
-    statusCode := 0
-    fetched := 0
-    offset := start
-
-    goCtx := context.Background()
-
-    for {
-        if fetched >= maxRows {
-            break
-        }
-
-        u, err := url.Parse(fmt.Sprintf("%s/api-gateway/api/v2/dtables/%s/rows/", cfg.Server, cfg.BaseUUID))
-        if err != nil {
-            return fmt.Errorf("parse rows URL: %w", err)
-        }
-        q := u.Query()
-        q.Set("table_name", tableName)
-        if strings.TrimSpace(viewName) != "" {
-            q.Set("view_name", viewName)
-        }
-        q.Set("start", strconv.Itoa(offset))
-
-        remaining := maxRows - fetched
-        limit := pageSize
-        if remaining < limit {
-            limit = remaining
-        }
-        q.Set("limit", strconv.Itoa(limit))
-        if convert {
-            q.Set("convert_keys", "true")
-        }
-        u.RawQuery = q.Encode()
-
-        respBody, sc, err := doSeaTableRequest(goCtx, "GET", u.String(), cfg.Token, nil)
-        if err != nil {
-            return err
-        }
-        statusCode = sc
-
-        var parsed any
-        if err := json.Unmarshal(respBody, &parsed); err != nil {
-            return fmt.Errorf("unmarshal list rows response: %w", err)
-        }
-
-        var rowsPage []any
-        switch t := parsed.(type) {
-        case map[string]any:
-            if v, ok := t["rows"]; ok {
-                if arr, ok := v.([]any); ok {
-                    rowsPage = arr
-                }
-            }
-        case []any:
-            rowsPage = t
-        }
-
-        if len(rowsPage) == 0 {
-            break
-        }
-
-        allRows = append(allRows, rowsPage...)
-        fetched += len(rowsPage)
-        offset += len(rowsPage)
-
-        if len(rowsPage) < limit {
-            break
-        }
+    if tableName, err = resolveTableName(goCtx, cfg, tableName); err != nil {
+        return err
     }
 
-    n.OutStatusCode.Set(ctx, statusCode)
-    n.OutRows.Set(ctx, allRows)
+    allRows, resp, err := cfg.FetchRows(goCtx, tableName, &seatable.FetchRowsOptions{
+        View:        viewName,
+        Filter:      filter,
+        Start:       start,
+        MaxRows:     maxRows,
+        PageSize:    pageSize,
+        ConvertKeys: convert,
+        Concurrency: concurrency,
+    })
+    if err := checkSeaTableError(failOnError, err); err != nil {
+        return err
+    }
+
+    n.OutStatusCode.Set(ctx, resp.StatusCode)
+    large, err := storeLarge[any](ctx, &n.OutRows, allRows)
+    if err != nil {
+        return err
+    }
     result := map[string]any{
-        "rows":  allRows,
         "count": len(allRows),
         "start": start,
     }
-    n.OutJSON.Set(ctx, result)
-    return nil
+    // Large row sets are stored once, as Rows.
+    if !large {
+        result["rows"] = allRows
+    }
+    return n.OutJSON.Set(ctx, result)
 }
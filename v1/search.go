@@ -1,11 +1,10 @@
 package v1
 
 import (
-    "context"
     "encoding/json"
-    "fmt"
     "strings"
 
+    "github.com/example/robomotion-seatable/seatable"
     "github.com/robomotionio/robomotion-go/message"
     "github.com/robomotionio/robomotion-go/runtime"
 )
@@ -23,16 +22,24 @@ type SeaTableSearch struct {
     OptCaseSensitive runtime.OptVariable[bool] `spec:"title=Case Sensitive,type=bool,value=false,scope=Message,name=caseSensitive,messageScope,customScope,jsScope"`
     OptMaxRows       runtime.OptVariable[int]  `spec:"title=Max Rows,type=int,value=100,scope=Message,name=maxRows,messageScope,customScope,jsScope"`
     OptConvert       runtime.OptVariable[bool] `spec:"title=Convert Keys,type=bool,value=true,scope=Message,name=convertKeys,messageScope,customScope,jsScope"`
+    OptFailOnError runtime.OptVariable[bool] `spec:"title=Fail on HTTP Error,type=bool,value=false,scope=Message,name=failOnError,messageScope,customScope,jsScope"`
+    OptTimeout runtime.OptVariable[int] `spec:"title=Timeout (seconds),type=int,value=0,scope=Message,name=timeout,messageScope,customScope,jsScope"`
 
     OutStatusCode runtime.OutVariable[int]    `spec:"title=Status Code,type=int,scope=Message,name=statusCode,messageScope"`
     OutRaw        runtime.OutVariable[string] `spec:"title=Raw Body,type=string,scope=Message,name=body,messageScope"`
     OutJSON       runtime.OutVariable[any]    `spec:"title=JSON,type=object,scope=Message,name=json,messageScope"`
     OutRows       runtime.OutVariable[any]    `spec:"title=Rows,type=object,scope=Message,name=rows,messageScope"`
     OutCount      runtime.OutVariable[int]    `spec:"title=Count,type=int,scope=Message,name=count,messageScope"`
+
+    requests nodeRequests
 }
 
 func (n *SeaTableSearch) OnCreate() error { return nil }
-func (n *SeaTableSearch) OnClose() error  { return nil }
+
+func (n *SeaTableSearch) OnClose() error {
+    n.requests.cancelAll()
+    return nil
+}
 
 func (n *SeaTableSearch) OnMessage(ctx message.Context) error {
     clientID, err := n.InClientID.Get(ctx)
@@ -83,79 +90,77 @@ func (n *SeaTableSearch) OnMessage(ctx message.Context) error {
     }
     convert, _ := n.OptConvert.Get(ctx)
 
-    var conditions []string
-    var params []any
+    timeout, _ := n.OptTimeout.Get(ctx)
+    goCtx, done := n.requests.begin(timeout)
+    defer done()
 
+    // The builder checks table and column names against the metadata and
+    // quotes them, so user supplied column lists cannot alter the query.
+    table, err := cfg.ResolveTable(goCtx, tableName)
+    if err != nil {
+        return seaTableError(err)
+    }
+
+    op, pattern := "LIKE", "%"+keyword+"%"
+    switch matchMode {
+    case "equals":
+        op, pattern = "=", keyword
+    case "startsWith":
+        pattern = keyword + "%"
+    case "endsWith":
+        pattern = "%" + keyword
+    }
+
+    query := seatable.NewSelect(table).AnyOf().Limit(maxRows)
     for _, col := range cols {
-        if !caseSensitive {
-            switch matchMode {
-            case "equals":
-                conditions = append(conditions, fmt.Sprintf("LOWER(%s) = ?", col))
-                params = append(params, strings.ToLower(keyword))
-            case "startsWith":
-                conditions = append(conditions, fmt.Sprintf("LOWER(%s) LIKE ?", col))
-                params = append(params, strings.ToLower(keyword)+"%")
-            case "endsWith":
-                conditions = append(conditions, fmt.Sprintf("LOWER(%s) LIKE ?", col))
-                params = append(params, "%"+strings.ToLower(keyword))
-            default:
-                conditions = append(conditions, fmt.Sprintf("LOWER(%s) LIKE ?", col))
-                params = append(params, "%"+strings.ToLower(keyword)+"%")
-            }
+        if caseSensitive {
+            query.Where(col, op, pattern)
         } else {
-            switch matchMode {
-            case "equals":
-                conditions = append(conditions, fmt.Sprintf("%s = ?", col))
-                params = append(params, keyword)
-            case "startsWith":
-                conditions = append(conditions, fmt.Sprintf("%s LIKE ?", col))
-                params = append(params, keyword+"%")
-            case "endsWith":
-                conditions = append(conditions, fmt.Sprintf("%s LIKE ?", col))
-                params = append(params, "%"+keyword)
-            default:
-                conditions = append(conditions, fmt.Sprintf("%s LIKE ?", col))
-                params = append(params, "%"+keyword+"%")
-            }
+            query.WhereLower(col, op, strings.ToLower(pattern))
         }
     }
-
-    whereClause := strings.Join(conditions, " OR ")
-    sqlText := fmt.Sprintf("SELECT * FROM %s WHERE %s LIMIT %d", tableName, whereClause, maxRows)
-
-    body := map[string]any{
-        "sql":          sqlText,
-        "params":       params,
-        "convert_keys": convert,
+    sqlText, params, err := query.Build()
+    if err != nil {
+        return seaTableError(err)
     }
 
-    url := fmt.Sprintf("%s/api-gateway/api/v2/dtables/%s/sql/", cfg.Server, cfg.BaseUUID)
-    respBody, status, err := doSeaTableRequest(context.Background(), "POST", url, cfg.Token, body)
-    if err != nil {
+    result, resp, err := cfg.QuerySQL(goCtx, sqlText, &seatable.SQLOptions{
+        Params:      params,
+        ConvertKeys: convert,
+    })
+    failOnError, _ := n.OptFailOnError.Get(ctx)
+    if err := checkSeaTableError(failOnError, err); err != nil {
         return err
     }
 
-    n.OutStatusCode.Set(ctx, status)
-    n.OutRaw.Set(ctx, string(respBody))
+    n.OutStatusCode.Set(ctx, resp.StatusCode)
 
-    var parsed any
-    if err := json.Unmarshal(respBody, &parsed); err == nil {
-        n.OutJSON.Set(ctx, parsed)
+    rows := []seatable.Row{}
+    if result != nil {
+        rows = result.Results
+    }
+    n.OutCount.Set(ctx, len(rows))
+    large, err := storeLarge[any](ctx, &n.OutRows, rows)
+    if err != nil {
+        return err
     }
 
-    var rows []any
-    if m, ok := parsed.(map[string]any); ok {
-        if v, ok := m["results"]; ok {
-            if arr, ok := v.([]any); ok {
-                rows = arr
-            }
+    var parsed any
+    if err := json.Unmarshal(resp.Body, &parsed); err != nil {
+        n.OutRaw.Set(ctx, string(resp.Body))
+        return nil
+    }
+    if large {
+        // The rows are stored once, as Rows. Raw Body is left empty and JSON
+        // keeps the rest of the response instead of repeating them.
+        if m, ok := parsed.(map[string]any); ok {
+            delete(m, "results")
         }
+        n.OutRaw.Set(ctx, "")
+    } else {
+        n.OutRaw.Set(ctx, string(resp.Body))
     }
-    if rows == nil {
-        rows = []any{}
-    }
-    n.OutRows.Set(ctx, rows)
-    n.OutCount.Set(ctx, len(rows))
+    n.OutJSON.Set(ctx, parsed)
 
     return nil
 }
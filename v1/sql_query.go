@@ -1,16 +1,16 @@
 package v1
 
 import (
-    "context"
     "encoding/json"
-    "fmt"
     "strings"
 
+    "github.com/example/robomotion-seatable/seatable"
     "github.com/robomotionio/robomotion-go/message"
     "github.com/robomotionio/robomotion-go/runtime"
 )
 
-// SeaTableSQLQuery executes arbitrary SQL against a SeaTable base.
+// SeaTableSQLQuery executes arbitrary SQL against a SeaTable base. With
+// Auto-paginate a SELECT is read page by page past the server's row limit.
 type SeaTableSQLQuery struct {
     runtime.Node `spec:"id=Robomotion.SeaTable.SQLQuery,name=SQL Query,icon=mdiCodeBraces,color=#00C2E0,inputs=1,outputs=1"`
 
@@ -18,14 +18,26 @@ type SeaTableSQLQuery struct {
     InSQL      runtime.InVariable[string]  `spec:"title=SQL,type=string,scope=Message,name=sql,messageScope,jsScope,customScope"`
     OptParams  runtime.OptVariable[any]    `spec:"title=Params,type=object,scope=Message,name=params,messageScope,customScope,jsScope"`
     OptConvert runtime.OptVariable[bool]   `spec:"title=Convert Keys,type=bool,value=true,scope=Message,name=convertKeys,messageScope,customScope,jsScope"`
+    OptAutoPaginate runtime.OptVariable[bool] `spec:"title=Auto-paginate,type=bool,value=false,scope=Message,name=autoPaginate,messageScope,customScope,jsScope"`
+    OptMaxRows     runtime.OptVariable[int]  `spec:"title=Max Rows,type=int,value=100000,scope=Message,name=maxRows,messageScope,customScope,jsScope"`
+    OptTypedOutput runtime.OptVariable[bool] `spec:"title=Typed Output,type=bool,value=false,scope=Message,name=typedOutput,messageScope,customScope,jsScope"`
+    OptFailOnError runtime.OptVariable[bool] `spec:"title=Fail on HTTP Error,type=bool,value=false,scope=Message,name=failOnError,messageScope,customScope,jsScope"`
+    OptTimeout runtime.OptVariable[int] `spec:"title=Timeout (seconds),type=int,value=0,scope=Message,name=timeout,messageScope,customScope,jsScope"`
 
     OutStatusCode runtime.OutVariable[int]         `spec:"title=Status Code,type=int,scope=Message,name=statusCode,messageScope"`
     OutRaw        runtime.OutVariable[string]      `spec:"title=Raw Body,type=string,scope=Message,name=body,messageScope"`
     OutJSON       runtime.OutVariable[any]         `spec:"title=JSON,type=object,scope=Message,name=json,messageScope"`
+    OutTruncated  runtime.OutVariable[bool]        `spec:"title=Truncated,type=bool,scope=Message,name=truncated,messageScope"`
+
+    requests nodeRequests
 }
 
 func (n *SeaTableSQLQuery) OnCreate() error { return nil }
-func (n *SeaTableSQLQuery) OnClose() error  { return nil }
+
+func (n *SeaTableSQLQuery) OnClose() error {
+    n.requests.cancelAll()
+    return nil
+}
 
 func (n *SeaTableSQLQuery) OnMessage(ctx message.Context) error {
     clientID, err := n.InClientID.Get(ctx)
@@ -45,11 +57,17 @@ func (n *SeaTableSQLQuery) OnMessage(ctx message.Context) error {
         return runtime.NewError("ErrInvalidArg", "SQL is required")
     }
 
-    var params []any
+    // An object binds :name placeholders, anything else the ? placeholders.
+    var (
+        params []any
+        named  map[string]any
+    )
     if v, err := n.OptParams.Get(ctx); err == nil && v != nil {
         switch t := v.(type) {
         case []any:
             params = t
+        case map[string]any:
+            named = t
         default:
             b, _ := json.Marshal(v)
             _ = json.Unmarshal(b, &params)
@@ -58,26 +76,68 @@ func (n *SeaTableSQLQuery) OnMessage(ctx message.Context) error {
 
     convert, _ := n.OptConvert.Get(ctx)
 
-    body := map[string]any{
-        "sql": sqlText,
+    timeout, _ := n.OptTimeout.Get(ctx)
+    goCtx, done := n.requests.begin(timeout)
+    defer done()
+
+    opts := &seatable.SQLOptions{
+        Params:      params,
+        Named:       named,
+        ConvertKeys: convert,
     }
-    if len(params) > 0 {
-        body["params"] = params
+    var (
+        result    *seatable.SQLResult
+        resp      *seatable.Response
+        truncated bool
+    )
+    autoPaginate, _ := n.OptAutoPaginate.Get(ctx)
+    if autoPaginate {
+        maxRows, _ := n.OptMaxRows.Get(ctx)
+        result, truncated, resp, err = cfg.QuerySQLAll(goCtx, sqlText, opts, maxRows)
+    } else {
+        result, resp, err = cfg.QuerySQL(goCtx, sqlText, opts)
     }
-    body["convert_keys"] = convert
-
-    url := fmt.Sprintf("%s/api-gateway/api/v2/dtables/%s/sql/", cfg.Server, cfg.BaseUUID)
-    respBody, status, err := doSeaTableRequest(context.Background(), "POST", url, cfg.Token, body)
-    if err != nil {
+    failOnError, _ := n.OptFailOnError.Get(ctx)
+    if err := checkSeaTableError(failOnError, err); err != nil {
         return err
     }
 
-    n.OutStatusCode.Set(ctx, status)
-    n.OutRaw.Set(ctx, string(respBody))
+    // Merged pages have no single response body; report them in the shape
+    // of one.
+    body := resp.Body
+    if autoPaginate && err == nil {
+        body, _ = json.Marshal(struct {
+            *seatable.SQLResult
+            Truncated bool `json:"truncated"`
+        }{result, truncated})
+    }
+
+    n.OutStatusCode.Set(ctx, resp.StatusCode)
+    n.OutTruncated.Set(ctx, truncated)
 
     var parsed any
-    if err := json.Unmarshal(respBody, &parsed); err == nil {
-        n.OutJSON.Set(ctx, parsed)
+    if err := json.Unmarshal(body, &parsed); err != nil {
+        return setLarge(ctx, &n.OutRaw, string(body))
     }
-    return nil
+    // Typed Output decodes results with the column metadata the SQL API
+    // returns next to them.
+    if typed, _ := n.OptTypedOutput.Get(ctx); typed && result != nil && err == nil {
+        if m, ok := parsed.(map[string]any); ok {
+            decoder, decErr := cfg.ColumnDecoder(goCtx, result.Metadata)
+            if decErr != nil {
+                return seaTableError(decErr)
+            }
+            m["results"] = decoder.Rows(result.Results)
+        }
+    }
+    large, err := storeLarge[any](ctx, &n.OutJSON, parsed)
+    if err != nil {
+        return err
+    }
+    // A large result is stored once, as JSON; Raw Body is left empty
+    // instead of repeating it.
+    if large {
+        return n.OutRaw.Set(ctx, "")
+    }
+    return n.OutRaw.Set(ctx, string(body))
 }
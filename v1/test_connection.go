@@ -0,0 +1,119 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/example/robomotion-seatable/seatable"
+	"github.com/robomotionio/robomotion-go/message"
+	"github.com/robomotionio/robomotion-go/runtime"
+)
+
+// SeaTableTestConnection checks that a client can reach the server and its
+// base, and reports what the token is allowed to do.
+type SeaTableTestConnection struct {
+	runtime.Node `spec:"id=Robomotion.SeaTable.TestConnection,name=Test Connection,icon=mdiLanCheck,color=#00C2E0,inputs=1,outputs=1"`
+
+	InClientID runtime.InVariable[string] `spec:"title=Client ID,type=string,scope=Message,name=clientId,messageScope,jsScope,customScope"`
+
+	OptTimeout runtime.OptVariable[int] `spec:"title=Timeout (seconds),type=int,value=0,scope=Message,name=timeout,messageScope,customScope,jsScope"`
+
+	OutServerVersion    runtime.OutVariable[string] `spec:"title=Server Version,type=string,scope=Message,name=serverVersion,messageScope"`
+	OutBaseName         runtime.OutVariable[string] `spec:"title=Base Name,type=string,scope=Message,name=baseName,messageScope"`
+	OutWorkspaceID      runtime.OutVariable[int]    `spec:"title=Workspace ID,type=int,scope=Message,name=workspaceId,messageScope"`
+	OutPermission       runtime.OutVariable[string] `spec:"title=Permission,type=string,scope=Message,name=permission,messageScope"`
+	OutGatewayReachable runtime.OutVariable[bool]   `spec:"title=API Gateway Reachable,type=bool,scope=Message,name=gatewayReachable,messageScope"`
+	OutJSON             runtime.OutVariable[any]    `spec:"title=JSON,type=object,scope=Message,name=json,messageScope"`
+
+	requests nodeRequests
+}
+
+func (n *SeaTableTestConnection) OnCreate() error { return nil }
+
+func (n *SeaTableTestConnection) OnClose() error {
+	n.requests.cancelAll()
+	return nil
+}
+
+func (n *SeaTableTestConnection) OnMessage(ctx message.Context) error {
+	clientID, err := n.InClientID.Get(ctx)
+	if err != nil {
+		return err
+	}
+	cfg, ok := getSeaTableClient(clientID)
+	if !ok {
+		return runtime.NewError("ErrInvalidArg", "Unknown Client ID – run SeaTable.Connect first")
+	}
+
+	timeout, _ := n.OptTimeout.Get(ctx)
+	goCtx, done := n.requests.begin(timeout)
+	defer done()
+
+	// 1. Server reachable and version.
+	info, _, err := cfg.GetServerInfo(goCtx)
+	if err != nil {
+		return runtime.NewError("ErrConnection", fmt.Sprintf("Cannot reach SeaTable server %s: %s", cfg.Server, errorMessage(err)))
+	}
+
+	// 2. Token valid: renew it when the client holds a long-lived credential
+	// so a revoked API token or account shows up here.
+	token, err := cfg.AccessToken(goCtx)
+	if err == nil && cfg.CanRefresh() {
+		token, err = cfg.RenewAccessToken(goCtx, token)
+	}
+	if err != nil {
+		return runtime.NewError("ErrUnauthorized", fmt.Sprintf("Token was rejected: %s", errorMessage(err)))
+	}
+	permission := ""
+	if claims, err := seatable.ParseAccessToken(token); err == nil {
+		permission = claims.Permission
+	}
+
+	// 3. Base accessible through the configured API flavor.
+	if _, _, err := cfg.GetMetadata(goCtx); err != nil {
+		var apiErr *seatable.APIError
+		if !errors.As(err, &apiErr) {
+			return runtime.NewError("ErrConnection", fmt.Sprintf("Cannot reach base %s: %s", cfg.BaseUUID, errorMessage(err)))
+		}
+		switch apiErr.StatusCode {
+		case 401, 403:
+			return runtime.NewError("ErrUnauthorized", fmt.Sprintf("Token has no access to base %s: %s", cfg.BaseUUID, apiErr.Message))
+		case 404:
+			return runtime.NewError("ErrNotFound", fmt.Sprintf("Base %s not found", cfg.BaseUUID))
+		}
+		return seaTableError(err)
+	}
+
+	// 4. API gateway reachable, even when the client uses legacy routes.
+	gatewayReachable := cfg.APIFlavor != seatable.APIFlavorLegacy || cfg.GatewayReachable(goCtx)
+
+	n.OutServerVersion.Set(ctx, info.Version)
+	n.OutBaseName.Set(ctx, cfg.BaseName)
+	n.OutWorkspaceID.Set(ctx, cfg.WorkspaceID)
+	n.OutPermission.Set(ctx, permission)
+	n.OutGatewayReachable.Set(ctx, gatewayReachable)
+	n.OutJSON.Set(ctx, map[string]any{
+		"serverVersion":    info.Version,
+		"edition":          info.Edition,
+		"apiFlavor":        cfg.APIFlavor,
+		"baseUuid":         cfg.BaseUUID,
+		"baseName":         cfg.BaseName,
+		"workspaceId":      cfg.WorkspaceID,
+		"permission":       permission,
+		"gatewayReachable": gatewayReachable,
+	})
+	return nil
+}
+
+// errorMessage returns the message of a runtime or API error, or err.Error().
+func errorMessage(err error) string {
+	var apiErr *seatable.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Message
+	}
+	var rerr *runtime.Error
+	if errors.As(err, &rerr) {
+		return rerr.Message
+	}
+	return err.Error()
+}
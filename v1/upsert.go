@@ -0,0 +1,143 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/example/robomotion-seatable/seatable"
+	"github.com/robomotionio/robomotion-go/message"
+	"github.com/robomotionio/robomotion-go/runtime"
+)
+
+// SeaTableUpsert inserts rows that do not exist yet and updates the ones that
+// do, matching on one or more key columns.
+type SeaTableUpsert struct {
+	runtime.Node `spec:"id=Robomotion.SeaTable.Upsert,name=Upsert,icon=mdiTableSync,color=#00C2E0,inputs=1,outputs=1"`
+
+	InClientID   runtime.InVariable[string] `spec:"title=Client ID,type=string,scope=Message,name=clientId,messageScope,jsScope,customScope"`
+	InTableName  runtime.InVariable[string] `spec:"title=Table Name,type=string,scope=Message,name=tableName,messageScope,jsScope,customScope"`
+	InRows       runtime.InVariable[any]    `spec:"title=Rows,type=object,scope=Message,name=rows,messageScope,jsScope,customScope"`
+	InKeyColumns runtime.InVariable[string] `spec:"title=Key Columns (comma separated),type=string,scope=Message,name=keyColumns,messageScope,jsScope,customScope"`
+
+	OptSkipUnchanged   runtime.OptVariable[bool] `spec:"title=Skip Unchanged Rows,type=bool,value=false,scope=Message,name=skipUnchanged,messageScope,customScope,jsScope"`
+	OptChunkSize       runtime.OptVariable[int]  `spec:"title=Chunk Size,type=int,value=1000,scope=Message,name=chunkSize,messageScope,customScope,jsScope"`
+	OptParallelism     runtime.OptVariable[int]  `spec:"title=Parallelism,type=int,value=1,scope=Message,name=parallelism,messageScope,customScope,jsScope"`
+	OptContinueOnError runtime.OptVariable[bool] `spec:"title=Continue on Chunk Error,type=bool,value=true,scope=Message,name=continueOnError,messageScope,customScope,jsScope"`
+	OptFailOnError     runtime.OptVariable[bool] `spec:"title=Fail on HTTP Error,type=bool,value=false,scope=Message,name=failOnError,messageScope,customScope,jsScope"`
+	OptTimeout         runtime.OptVariable[int]  `spec:"title=Timeout (seconds),type=int,value=0,scope=Message,name=timeout,messageScope,customScope,jsScope"`
+
+	OutInserted    runtime.OutVariable[int] `spec:"title=Inserted,type=int,scope=Message,name=inserted,messageScope"`
+	OutUpdated     runtime.OutVariable[int] `spec:"title=Updated,type=int,scope=Message,name=updated,messageScope"`
+	OutUnchanged   runtime.OutVariable[int] `spec:"title=Unchanged,type=int,scope=Message,name=unchanged,messageScope"`
+	OutFailed      runtime.OutVariable[int] `spec:"title=Failed,type=int,scope=Message,name=failed,messageScope"`
+	OutSkipped     runtime.OutVariable[int] `spec:"title=Skipped,type=int,scope=Message,name=skipped,messageScope"`
+	OutInsertedIDs runtime.OutVariable[any] `spec:"title=Inserted Row IDs,type=object,scope=Message,name=insertedIds,messageScope"`
+	OutUpdatedIDs  runtime.OutVariable[any] `spec:"title=Updated Row IDs,type=object,scope=Message,name=updatedIds,messageScope"`
+	OutJSON        runtime.OutVariable[any] `spec:"title=JSON,type=object,scope=Message,name=json,messageScope"`
+
+	requests nodeRequests
+}
+
+func (n *SeaTableUpsert) OnCreate() error { return nil }
+
+func (n *SeaTableUpsert) OnClose() error {
+	n.requests.cancelAll()
+	return nil
+}
+
+func (n *SeaTableUpsert) OnMessage(ctx message.Context) error {
+	clientID, err := n.InClientID.Get(ctx)
+	if err != nil {
+		return err
+	}
+	cfg, ok := getSeaTableClient(clientID)
+	if !ok {
+		return runtime.NewError("ErrInvalidArg", "Unknown Client ID – run SeaTable.Connect first")
+	}
+
+	tableName, err := n.InTableName.Get(ctx)
+	if err != nil {
+		return err
+	}
+	tableName = strings.TrimSpace(tableName)
+	if tableName == "" {
+		return runtime.NewError("ErrInvalidArg", "Table Name is required")
+	}
+
+	v, err := n.InRows.Get(ctx)
+	if err != nil {
+		return err
+	}
+	var rows []seatable.Row
+	if b, err := json.Marshal(v); err != nil || json.Unmarshal(b, &rows) != nil || len(rows) == 0 {
+		return runtime.NewError("ErrInvalidArg", "Rows must be a non-empty array of row objects")
+	}
+
+	keyStr, err := n.InKeyColumns.Get(ctx)
+	if err != nil {
+		return err
+	}
+	keys := splitColumns(keyStr)
+	if len(keys) == 0 {
+		return runtime.NewError("ErrInvalidArg", "At least one key column is required")
+	}
+
+	opts := &seatable.UpsertOptions{KeyColumns: keys}
+	opts.SkipUnchanged, _ = n.OptSkipUnchanged.Get(ctx)
+	opts.Batch.ChunkSize, _ = n.OptChunkSize.Get(ctx)
+	opts.Batch.Parallelism, _ = n.OptParallelism.Get(ctx)
+	if cont, err := n.OptContinueOnError.Get(ctx); err == nil {
+		opts.Batch.StopOnError = !cont
+	}
+
+	timeout, _ := n.OptTimeout.Get(ctx)
+	goCtx, done := n.requests.begin(timeout)
+	defer done()
+
+	if tableName, err = resolveTableName(goCtx, cfg, tableName); err != nil {
+		return err
+	}
+	if err := validateColumnNames(goCtx, cfg, tableName, rowColumns(rows, keys)); err != nil {
+		return err
+	}
+
+	result, err := cfg.UpsertRows(goCtx, tableName, rows, opts)
+	if result == nil || err != nil {
+		return seaTableError(err)
+	}
+	if failOnError, _ := n.OptFailOnError.Get(ctx); failOnError && result.Failed > 0 {
+		return runtime.NewError("ErrHTTP", fmt.Sprintf("%d of %d rows failed to upsert: %s", result.Failed, len(rows), result.Failures[0].Error))
+	}
+
+	n.OutInserted.Set(ctx, result.Inserted)
+	n.OutUpdated.Set(ctx, result.Updated)
+	n.OutUnchanged.Set(ctx, result.Unchanged)
+	n.OutFailed.Set(ctx, result.Failed)
+	n.OutSkipped.Set(ctx, result.Skipped)
+	n.OutInsertedIDs.Set(ctx, result.InsertedIDs)
+	n.OutUpdatedIDs.Set(ctx, result.UpdatedIDs)
+	n.OutJSON.Set(ctx, result)
+
+	return nil
+}
+
+// rowColumns returns the sorted set of column names used by rows and keys.
+func rowColumns(rows []seatable.Row, keys []string) []string {
+	seen := make(map[string]bool)
+	for _, k := range keys {
+		seen[k] = true
+	}
+	for _, row := range rows {
+		for k := range row {
+			seen[k] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for k := range seen {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}